@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/wisepythagoras/go-sudoku-gen/formats"
+)
+
+// runConvert converts a file of puzzles from one format to another (e.g. from an OpenSudoku
+// collection to SadMan Sudoku). The formats are derived from the extensions of the files, unless
+// they're given with -from and -to. A "-" stands for stdin or stdout.
+func runConvert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	fromPtr := flags.String("from", "", "The format of the input (sdk, sdm, ss or opensudoku); derived from its extension if left empty")
+	toPtr := flags.String("to", "", "The format of the output (sdk, sdm, ss or opensudoku); derived from its extension if left empty")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage of convert: [-from FORMAT] [-to FORMAT] INPUT OUTPUT")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return errors.New("convert needs an input and an output")
+	}
+
+	input, output := flags.Arg(0), flags.Arg(1)
+	from, err := fileFormat(input, *fromPtr)
+
+	if err != nil {
+		return err
+	}
+
+	to, err := fileFormat(output, *toPtr)
+
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+
+	if input != "-" {
+		f, err := os.Open(input)
+
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+		r = f
+	}
+
+	puzzles, err := formats.Read(r, from)
+
+	if err != nil {
+		return fmt.Errorf("unable to read %s: %w", input, err)
+	}
+
+	if output == "-" {
+		return formats.Write(os.Stdout, to, puzzles)
+	}
+
+	f, err := os.Create(output)
+
+	if err != nil {
+		return err
+	}
+
+	if err = formats.Write(f, to, puzzles); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// fileFormat returns the format of a file, which is either given by its name or derived from
+// the extension of the file.
+func fileFormat(path, name string) (formats.Format, error) {
+	if name != "" {
+		return formats.ParseFormat(name)
+	}
+
+	if path == "-" {
+		return formats.SDK, errors.New("the format of stdin and stdout has to be given with -from or -to")
+	}
+
+	return formats.FormatFromPath(path)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+// dailySaltEnv is the environment variable with the secret salt of the daily puzzles, which is
+// kept out of the flags so that it doesn't show up in the list of processes.
+const dailySaltEnv = "SUDOKU_DAILY_SALT"
+
+// runDaily shows the daily puzzles of a date (see `sudoku.DailyPuzzle`).
+func runDaily(args []string) error {
+	flags := flag.NewFlagSet("daily", flag.ExitOnError)
+	datePtr := flags.String("date", "", "The date of the puzzles as YYYY-MM-DD; defaults to today in UTC")
+	difficultyPtr := flags.String("difficulty", "", "The difficulties of the puzzles, separated by commas; defaults to easy,medium,hard")
+	simpleOutputPtr := flags.Bool("simple", false, "Shows a board without UTF-8 borders")
+	formatPtr := flags.String("format", "box", "The order of the cells in the puzzle strings (box, row or candidates)")
+	ndjsonPtr := flags.Bool("ndjson", false, "Writes the puzzles as JSON lines, instead of showing them")
+	flags.Parse(args)
+
+	salt := os.Getenv(dailySaltEnv)
+
+	if salt == "" {
+		return fmt.Errorf("the salt of the daily puzzles has to be set in %s", dailySaltEnv)
+	}
+
+	date, err := parseDate(*datePtr)
+
+	if err != nil {
+		return err
+	}
+
+	difficulties, err := sudoku.ParseDailyDifficulties(*difficultyPtr)
+
+	if err != nil {
+		return err
+	}
+
+	format, err := sudoku.ParseFormat(*formatPtr)
+
+	if err != nil {
+		return err
+	}
+
+	for _, difficulty := range difficulties {
+		start := time.Now()
+		puzzle, solution, err := sudoku.DailyPuzzle(date, salt, difficulty)
+
+		if err != nil {
+			return fmt.Errorf("the %s puzzle of %s: %w", strings.ToLower(difficulty.String()), date.Format(sudoku.DateFormat), err)
+		}
+
+		if *ndjsonPtr {
+			doc := sudoku.NewDocument(puzzle, solution)
+			doc.GenerationTime = float64(time.Since(start).Microseconds()) / 1000
+
+			if err = json.NewEncoder(os.Stdout).Encode(doc); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		fmt.Printf("%s puzzle of %s:\n", difficulty, date.Format(sudoku.DateFormat))
+		puzzle.Print(!*simpleOutputPtr)
+		fmt.Println("Puzzle string:")
+		fmt.Println(puzzle.Format(format))
+	}
+
+	return nil
+}
+
+// parseDate parses a date in the YYYY-MM-DD format. An empty string gives today, in UTC.
+func parseDate(date string) (time.Time, error) {
+	if date == "" {
+		return time.Now().UTC(), nil
+	}
+
+	parsed, err := time.Parse(sudoku.DateFormat, date)
+
+	if err != nil {
+		return parsed, fmt.Errorf("invalid date \"%s\", which should be like 2024-01-31", date)
+	}
+
+	return parsed, nil
+}
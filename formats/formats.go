@@ -0,0 +1,148 @@
+// Package formats reads and writes Sudoku puzzles in the file formats of other Sudoku software,
+// like SadMan Sudoku (.sdk and .sdm), Simple Sudoku (.ss) and OpenSudoku (.opensudoku).
+package formats
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+// Format is a file format of Sudoku puzzles.
+type Format int
+
+const (
+	SDK        Format = iota // SadMan Sudoku, a single puzzle as a grid with metadata headers.
+	SDM                      // SadMan Sudoku, many puzzles with one per line.
+	SS                       // Simple Sudoku, a single puzzle as a grid with box borders.
+	OpenSudoku               // OpenSudoku, an XML collection of puzzles.
+)
+
+var formatNames = []string{"sdk", "sdm", "ss", "opensudoku"}
+
+// String returns the name of the format, which is also its file extension.
+func (f Format) String() string {
+	if f < SDK || f > OpenSudoku {
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+
+	return formatNames[f]
+}
+
+// ParseFormat returns the format with a specific name, regardless of its case.
+func ParseFormat(name string) (Format, error) {
+	for i, formatName := range formatNames {
+		if strings.EqualFold(name, formatName) {
+			return Format(i), nil
+		}
+	}
+
+	return SDK, fmt.Errorf("unknown puzzle format \"%s\"", name)
+}
+
+// FormatFromPath returns the format of a file based on its extension. OpenSudoku collections
+// can also have the ".xml" extension.
+func FormatFromPath(path string) (Format, error) {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+
+	if strings.EqualFold(ext, "xml") {
+		return OpenSudoku, nil
+	}
+
+	return ParseFormat(ext)
+}
+
+// Multiple returns whether a file of the format can hold more than one puzzle.
+func (f Format) Multiple() bool {
+	return f == SDM || f == OpenSudoku
+}
+
+// Metadata holds the details of a puzzle that some formats keep along with it. All of them are
+// optional.
+type Metadata struct {
+	Title       string
+	Author      string
+	Description string
+	Comment     string
+	Date        string
+	Source      string
+	SourceURL   string
+	Level       string
+}
+
+// Puzzle is a puzzle that was read from a file, along with its metadata.
+type Puzzle struct {
+	Board *sudoku.Sudoku
+	Metadata
+}
+
+// Read reads all the puzzles of a file in a specific format.
+func Read(r io.Reader, format Format) ([]*Puzzle, error) {
+	switch format {
+	case SDK:
+		puzzle, err := ReadSDK(r)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []*Puzzle{puzzle}, nil
+	case SDM:
+		return ReadSDM(r)
+	case SS:
+		puzzle, err := ReadSS(r)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []*Puzzle{puzzle}, nil
+	case OpenSudoku:
+		return ReadOpenSudoku(r)
+	}
+
+	return nil, fmt.Errorf("unknown puzzle format %d", int(format))
+}
+
+// Write writes puzzles to a file in a specific format. The formats which only hold a single
+// puzzle (see `Multiple`) return an error if there are more.
+func Write(w io.Writer, format Format, puzzles []*Puzzle) error {
+	if !format.Multiple() && len(puzzles) != 1 {
+		return fmt.Errorf("the %s format holds a single puzzle, not %d", format, len(puzzles))
+	}
+
+	switch format {
+	case SDK:
+		return WriteSDK(w, puzzles[0])
+	case SDM:
+		return WriteSDM(w, puzzles)
+	case SS:
+		return WriteSS(w, puzzles[0])
+	case OpenSudoku:
+		return WriteOpenSudoku(w, puzzles)
+	}
+
+	return fmt.Errorf("unknown puzzle format %d", int(format))
+}
+
+// rows returns the rows of a board as strings, with `blank` for each empty cell.
+func rows(board *sudoku.Sudoku, blank byte) []string {
+	n := int(board.N)
+	cells := []byte(board.RowMajorString())
+	rows := make([]string, n)
+
+	for i := range cells {
+		if cells[i] == '.' {
+			cells[i] = blank
+		}
+	}
+
+	for row := range rows {
+		rows[row] = string(cells[row*n : row*n+n])
+	}
+
+	return rows
+}
@@ -0,0 +1,150 @@
+package formats_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/wisepythagoras/go-sudoku-gen/formats"
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+const puzzleRows = "4.1..38......2....7....5..6......2.5.7..5..9.3.4......9..5....2....1......63..1.4"
+
+const sdkFile = `#AJohn Doe
+#DA sample puzzle
+#L3
+[Puzzle]
+4.1..38..
+....2....
+7....5..6
+......2.5
+.7..5..9.
+3.4......
+9..5....2
+....1....
+..63..1.4
+[State]
+421..38..
+`
+
+const ssFile = `4.1|..3|8..
+...|.2.|...
+7..|..5|..6
+-----------
+...|...|2.5
+.7.|.5.|.9.
+3.4|...|...
+-----------
+9..|5..|..2
+...|.1.|...
+..6|3..|1.4
+`
+
+const openSudokuFile = `<?xml version="1.0" encoding="UTF-8"?>
+<opensudoku>
+  <name>Samples</name>
+  <author>John Doe</author>
+  <game data="401003800000020000700005006000000205070050090304000000900500002000010000006300104"></game>
+  <game data="100000002090400050006000700050903000000070000000850040700000600030009080002000001"></game>
+</opensudoku>
+`
+
+func TestReadFormats(t *testing.T) {
+	puzzle, err := formats.ReadSDK(strings.NewReader(sdkFile))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if puzzle.Board.RowMajorString() != puzzleRows || puzzle.Author != "John Doe" || puzzle.Level != "3" {
+		t.Errorf("Unexpected SDK puzzle %s by %s", puzzle.Board.RowMajorString(), puzzle.Author)
+	}
+
+	if puzzle, err = formats.ReadSS(strings.NewReader(ssFile)); err != nil || puzzle.Board.RowMajorString() != puzzleRows {
+		t.Errorf("Unable to read the SS puzzle: %v", err)
+	}
+
+	puzzles, err := formats.ReadOpenSudoku(strings.NewReader(openSudokuFile))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(puzzles) != 2 || puzzles[0].Board.RowMajorString() != puzzleRows || puzzles[1].Title != "Samples" {
+		t.Errorf("Unexpected OpenSudoku puzzles %v", puzzles)
+	}
+
+	if _, err := formats.ReadSDM(strings.NewReader("4.1..38..\n")); err == nil {
+		t.Error("An SDM line with an invalid length was read")
+	}
+}
+
+func TestWriteFormats(t *testing.T) {
+	board := &sudoku.Sudoku{N: 6, Seed: 2}
+	board.Init()
+	board.Fill()
+
+	puzzles := []*formats.Puzzle{
+		{Board: board.GeneratePuzzle(), Metadata: formats.Metadata{Author: "Jane Doe", Title: "Sixes"}},
+		{Board: board},
+	}
+
+	for _, format := range []formats.Format{formats.SDK, formats.SDM, formats.SS, formats.OpenSudoku} {
+		written := puzzles
+
+		if !format.Multiple() {
+			written = puzzles[:1]
+		}
+
+		var buf bytes.Buffer
+
+		if err := formats.Write(&buf, format, written); err != nil {
+			t.Fatalf("Unable to write the %s format: %v", format, err)
+		}
+
+		read, err := formats.Read(&buf, format)
+
+		if err != nil {
+			t.Fatalf("Unable to read the %s format: %v", format, err)
+		}
+
+		if len(read) != len(written) {
+			t.Fatalf("Expected %d %s puzzles, got %d", len(written), format, len(read))
+		}
+
+		for i, puzzle := range read {
+			if !puzzle.Board.IsEqual(written[i].Board) {
+				t.Errorf("Puzzle %d of the %s format didn't round-trip", i+1, format)
+			}
+		}
+
+		if format == formats.SDK && read[0].Author != "Jane Doe" {
+			t.Error("The metadata of the SDK format didn't round-trip")
+		}
+	}
+
+	if err := formats.Write(&bytes.Buffer{}, formats.SS, puzzles); err == nil {
+		t.Error("Two puzzles were written in the SS format")
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	paths := map[string]formats.Format{
+		"a.sdk":        formats.SDK,
+		"b/c.SDM":      formats.SDM,
+		"d.ss":         formats.SS,
+		"e.opensudoku": formats.OpenSudoku,
+		"f.xml":        formats.OpenSudoku,
+	}
+
+	for path, expected := range paths {
+		if format, err := formats.FormatFromPath(path); err != nil || format != expected {
+			t.Errorf("Expected %s for %s, got %s", expected, path, format)
+		}
+	}
+
+	if _, err := formats.FormatFromPath("g.txt"); err == nil {
+		t.Error("An unknown extension was given a format")
+	}
+}
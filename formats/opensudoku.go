@@ -0,0 +1,101 @@
+package formats
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+// openSudoku is the root element of an OpenSudoku collection.
+type openSudoku struct {
+	XMLName     xml.Name         `xml:"opensudoku"`
+	Name        string           `xml:"name,omitempty"`
+	Author      string           `xml:"author,omitempty"`
+	Description string           `xml:"description,omitempty"`
+	Comment     string           `xml:"comment,omitempty"`
+	Created     string           `xml:"created,omitempty"`
+	Source      string           `xml:"source,omitempty"`
+	Level       string           `xml:"level,omitempty"`
+	SourceURL   string           `xml:"sourceURL,omitempty"`
+	Games       []openSudokuGame `xml:"game"`
+}
+
+// openSudokuGame is a puzzle of an OpenSudoku collection, with its cells listed row by row and a
+// "0" for each empty one.
+type openSudokuGame struct {
+	Data string `xml:"data,attr"`
+}
+
+// ReadOpenSudoku reads the puzzles of an OpenSudoku collection. The metadata of the collection
+// is given to every puzzle.
+func ReadOpenSudoku(r io.Reader) ([]*Puzzle, error) {
+	var collection openSudoku
+
+	if err := xml.NewDecoder(r).Decode(&collection); err != nil {
+		return nil, err
+	}
+
+	metadata := Metadata{
+		Title:       strings.TrimSpace(collection.Name),
+		Author:      strings.TrimSpace(collection.Author),
+		Description: strings.TrimSpace(collection.Description),
+		Comment:     strings.TrimSpace(collection.Comment),
+		Date:        strings.TrimSpace(collection.Created),
+		Source:      strings.TrimSpace(collection.Source),
+		SourceURL:   strings.TrimSpace(collection.SourceURL),
+		Level:       strings.TrimSpace(collection.Level),
+	}
+	puzzles := make([]*Puzzle, 0, len(collection.Games))
+
+	for i, game := range collection.Games {
+		board, err := sudoku.ParseRowMajor(game.Data)
+
+		if err != nil {
+			return nil, fmt.Errorf("game %d: %w", i+1, err)
+		}
+
+		puzzles = append(puzzles, &Puzzle{Board: board, Metadata: metadata})
+	}
+
+	return puzzles, nil
+}
+
+// WriteOpenSudoku writes puzzles as an OpenSudoku collection (see `ReadOpenSudoku`). The
+// metadata of the collection is taken from the first puzzle.
+func WriteOpenSudoku(w io.Writer, puzzles []*Puzzle) error {
+	collection := openSudoku{Games: make([]openSudokuGame, 0, len(puzzles))}
+
+	if len(puzzles) > 0 {
+		metadata := puzzles[0].Metadata
+		collection.Name = metadata.Title
+		collection.Author = metadata.Author
+		collection.Description = metadata.Description
+		collection.Comment = metadata.Comment
+		collection.Created = metadata.Date
+		collection.Source = metadata.Source
+		collection.Level = metadata.Level
+		collection.SourceURL = metadata.SourceURL
+	}
+
+	for _, puzzle := range puzzles {
+		collection.Games = append(collection.Games, openSudokuGame{Data: strings.Join(rows(puzzle.Board, '0'), "")})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(collection); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
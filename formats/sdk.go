@@ -0,0 +1,146 @@
+package formats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+// ReadSDK reads a puzzle in the SadMan Sudoku format, which is a grid with a row per line and a
+// "." for each empty cell, after the metadata headers (e.g. "#A" for the author). Only the
+// "[Puzzle]" section of the newer version of the format is read, if there are sections.
+func ReadSDK(r io.Reader) (*Puzzle, error) {
+	puzzle := &Puzzle{}
+	var grid strings.Builder
+	scanner := bufio.NewScanner(r)
+	inPuzzle := true
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case strings.HasPrefix(line, "["):
+			inPuzzle = strings.EqualFold(line, "[Puzzle]")
+		case strings.HasPrefix(line, "#") && len(line) >= 2:
+			puzzle.setHeader(line[1], strings.TrimSpace(line[2:]))
+		case inPuzzle:
+			grid.WriteString(line)
+			grid.WriteByte('\n')
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	board, err := sudoku.ParseRowMajor(grid.String())
+
+	if err != nil {
+		return nil, err
+	}
+
+	puzzle.Board = board
+
+	return puzzle, nil
+}
+
+// WriteSDK writes a puzzle in the SadMan Sudoku format (see `ReadSDK`), along with the headers
+// of the metadata it has.
+func WriteSDK(w io.Writer, puzzle *Puzzle) error {
+	headers := []struct {
+		code  byte
+		value string
+	}{
+		{'A', puzzle.Author},
+		{'D', puzzle.Description},
+		{'C', puzzle.Comment},
+		{'B', puzzle.Date},
+		{'S', puzzle.Source},
+		{'L', puzzle.Level},
+		{'U', puzzle.SourceURL},
+	}
+
+	for _, header := range headers {
+		if header.value == "" {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "#%c%s\n", header.code, header.value); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range rows(puzzle.Board, '.') {
+		if _, err := fmt.Fprintln(w, row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadSDM reads the puzzles of a SadMan Sudoku collection, where each line is a puzzle with its
+// cells listed row by row and a "0" (or a ".") for each empty one.
+func ReadSDM(r io.Reader) ([]*Puzzle, error) {
+	puzzles := make([]*Puzzle, 0)
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		lineNum++
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		board, err := sudoku.ParseRowMajor(line)
+
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		puzzles = append(puzzles, &Puzzle{Board: board})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return puzzles, nil
+}
+
+// WriteSDM writes puzzles as a SadMan Sudoku collection (see `ReadSDM`). The format has no
+// metadata, so it's left out.
+func WriteSDM(w io.Writer, puzzles []*Puzzle) error {
+	for _, puzzle := range puzzles {
+		if _, err := fmt.Fprintln(w, strings.Join(rows(puzzle.Board, '0'), "")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setHeader sets the metadata of a SadMan Sudoku header. Unknown headers are ignored.
+func (p *Puzzle) setHeader(code byte, value string) {
+	switch code {
+	case 'A':
+		p.Author = value
+	case 'D':
+		p.Description = value
+	case 'C':
+		p.Comment = value
+	case 'B':
+		p.Date = value
+	case 'S':
+		p.Source = value
+	case 'L':
+		p.Level = value
+	case 'U':
+		p.SourceURL = value
+	}
+}
@@ -0,0 +1,60 @@
+package formats
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+// ReadSS reads a puzzle in the Simple Sudoku format, which is a grid with a row per line, a "."
+// for each empty cell and borders between the boxes, like:
+//
+//	..3|.1.|...
+//	...|...|...
+//	-----------
+func ReadSS(r io.Reader) (*Puzzle, error) {
+	data, err := io.ReadAll(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The borders are separators, which the row-major parser ignores.
+	board, err := sudoku.ParseRowMajor(string(data))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Puzzle{Board: board}, nil
+}
+
+// WriteSS writes a puzzle in the Simple Sudoku format (see `ReadSS`). The format has no
+// metadata, so it's left out.
+func WriteSS(w io.Writer, puzzle *Puzzle) error {
+	board := puzzle.Board
+	n, boxWidth, boxHeight := int(board.N), int(board.BoxWidth), int(board.BoxHeight)
+	border := strings.Repeat("-", n+n/boxWidth-1)
+
+	for row, cells := range rows(board, '.') {
+		if row > 0 && row%boxHeight == 0 {
+			if _, err := fmt.Fprintln(w, border); err != nil {
+				return err
+			}
+		}
+
+		boxes := make([]string, 0, n/boxWidth)
+
+		for col := 0; col < n; col += boxWidth {
+			boxes = append(boxes, cells[col:col+boxWidth])
+		}
+
+		if _, err := fmt.Fprintln(w, strings.Join(boxes, "|")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
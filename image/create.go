@@ -1,7 +1,6 @@
 package image
 
 import (
-	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
@@ -13,69 +12,78 @@ import (
 	"golang.org/x/image/math/fixed"
 )
 
+// imageSize is the width and height of the generated images.
+const imageSize = 1031
+
 // Create the image for a Sudoku puzzle.
 func CreateImage(puzzle *sudoku.Sudoku) (*image.RGBA, error) {
-	img := image.NewRGBA(image.Rect(0, 0, 1031, 1031))
-
-	drawGrid(img)
+	n := int(puzzle.N)
+	img := image.NewRGBA(image.Rect(0, 0, imageSize, imageSize))
 
-	// Loop through the rows.
-	for i := 0; i < 3; i++ {
-		box1 := puzzle.GetBox(i * 3)
-		box2 := puzzle.GetBox(i*3 + 1)
-		box3 := puzzle.GetBox(i*3 + 2)
-		startVPos := 85 + (110 * i * 3)
-
-		if i == 2 {
-			startVPos += 9
-		} else if i == 1 {
-			startVPos += 3
-		}
+	drawGrid(img, n, int(puzzle.BoxWidth), int(puzzle.BoxHeight))
 
-		for j := 0; j < 3; j++ {
-			row := make([]uint8, 3)
-			vPos := startVPos + (110 * j)
+	myFont, err := opentype.Parse(goregular.TTF)
 
-			copy(row, box1.GetRow(j))
+	if err != nil {
+		return nil, err
+	}
 
-			row = append(row, box2.GetRow(j)...)
-			row = append(row, box3.GetRow(j)...)
+	// The font is sized for the 9x9 board and shrinks along with the cells of bigger boards.
+	fontFace, err := opentype.NewFace(myFont, &opentype.FaceOptions{
+		Size: 16 * 9 / float64(n),
+		DPI:  300.,
+	})
 
-			for k, n := range row {
-				if n == 0 {
-					continue
-				}
+	if err != nil {
+		return nil, err
+	}
 
-				hPos := 55 + 110*k
+	defer fontFace.Close()
 
-				if k >= 6 {
-					hPos += 9
-				} else if k >= 3 {
-					hPos += 3
-				}
+	// Loop through the rows.
+	for i := 0; i < n; i++ {
+		vPos := (cellPos(n, i) + cellPos(n, i+1)) / 2
 
-				label := fmt.Sprintf("%d", n)
-				addLabel(img, hPos, vPos, label)
+		for k, v := range puzzle.GetRow(i) {
+			if v == 0 {
+				continue
 			}
+
+			hPos := (cellPos(n, k) + cellPos(n, k+1)) / 2
+
+			addLabel(img, fontFace, hPos, vPos, string(sudoku.Symbol(v)))
 		}
 	}
 
 	return img, nil
 }
 
-func drawGrid(img *image.RGBA) {
-	draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.ZP, draw.Src)
+// cellPos returns the position of the line before the row or column `i` of a board with `n`
+// rows and columns.
+func cellPos(n, i int) int {
+	return 10 + (imageSize-21)*i/n
+}
 
-	drawRectangle(img, color.Black, 5, 5, 1025, 1025, 10)
-	drawRectangle(img, color.Black, 344, 9, 344, 1020, 5)
-	drawRectangle(img, color.Black, 678, 9, 678, 1020, 5)
-	drawRectangle(img, color.Black, 9, 344, 1020, 344, 5)
-	drawRectangle(img, color.Black, 9, 678, 1020, 678, 5)
+func drawGrid(img *image.RGBA, n, boxWidth, boxHeight int) {
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
 
-	for i := 0; i < 9; i++ {
-		pos := 10 + 111*(i+1) + i - 1
-		drawRectangle(img, color.Black, pos, 10, pos, 1020, 2)
-		drawRectangle(img, color.Black, 10, pos, 1020, pos, 2)
+	drawRectangle(img, color.Black, 5, 5, imageSize-6, imageSize-6, 10)
+
+	for i := 1; i < n; i++ {
+		pos := cellPos(n, i)
+
+		// Boxes are separated with thicker lines.
+		if i%boxWidth == 0 {
+			drawRectangle(img, color.Black, pos-2, 9, pos+2, imageSize-11, 5)
+		} else {
+			drawRectangle(img, color.Black, pos-1, 10, pos, imageSize-11, 2)
+		}
+
+		if i%boxHeight == 0 {
+			drawRectangle(img, color.Black, 9, pos-2, imageSize-11, pos+2, 5)
+		} else {
+			drawRectangle(img, color.Black, 10, pos-1, imageSize-11, pos, 2)
+		}
 	}
 }
 
@@ -95,21 +103,20 @@ func drawRectangle(img draw.Image, color color.Color, x1, y1, x2, y2, thickness
 	}
 }
 
-func addLabel(img *image.RGBA, x, y int, label string) {
+func addLabel(img *image.RGBA, fontFace font.Face, x, y int, label string) {
 	col := color.RGBA{0, 0, 0, 255}
-	point := fixed.Point26_6{fixed.Int26_6((x - 4) * 64), fixed.Int26_6((y + 4) * 64)}
-
-	myFont, _ := opentype.Parse(goregular.TTF)
-	fontFace, _ := opentype.NewFace(myFont, &opentype.FaceOptions{
-		Size: 16,
-		DPI:  300.,
-	})
-
 	d := &font.Drawer{
 		Dst:  img,
 		Src:  image.NewUniform(col),
 		Face: fontFace,
-		Dot:  point,
+	}
+
+	// Center the label around the given point.
+	metrics := fontFace.Metrics()
+	width := d.MeasureString(label)
+	d.Dot = fixed.Point26_6{
+		X: fixed.I(x) - width/2,
+		Y: fixed.I(y) + (metrics.Ascent-metrics.Descent)/2,
 	}
 	d.DrawString(label)
 }
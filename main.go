@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"image/png"
@@ -12,6 +13,23 @@ import (
 )
 
 func main() {
+	// The commands have their own flags.
+	if len(os.Args) > 1 {
+		commands := map[string]func(args []string) error{
+			"convert": runConvert,
+			"daily":   runDaily,
+			"serve":   runServe,
+		}
+
+		if command, ok := commands[os.Args[1]]; ok {
+			if err := command(os.Args[2:]); err != nil {
+				fmt.Println(err)
+			}
+
+			return
+		}
+	}
+
 	curr := time.Now().UnixNano()
 	seedPtr := flag.Int64("seed", curr, "The seed; defaults to current unix timestamp")
 	simpleOutputPtr := flag.Bool("simple", false, "Shows a board without UTF-8 borders")
@@ -19,19 +37,102 @@ func main() {
 	saveImgPtr := flag.Bool("save-img", false, "Whether to save the image or not")
 	saveSolutionImgPtr := flag.Bool("save-solution-img", false, "Whether to save the image of the solution or not")
 	solvePtr := flag.String("solve", "", "A puzzle to solve")
+	loadPtr := flag.String("load", "", "A JSON file of a puzzle to solve, like the ones -output saves")
+	schemaPtr := flag.Bool("schema", false, "Shows the JSON Schema of the files that -output saves")
+	tracePtr := flag.String("trace", "", "Shows every step of the logical solution of -solve, as text or json")
+	hintPtr := flag.Int("hint", 0, "Shows a hint for the puzzle of -solve instead of solving it (1: where to look, 2: the technique, 3: the step)")
+	sizePtr := flag.Int("size", 9, "The number of rows and columns (e.g. 4, 6, 8, 9, 12, 16 or 25)")
+	boxPtr := flag.String("box", "", "The size of the boxes as WxH (e.g. 3x2); defaults to the squarest fit")
+	difficultyPtr := flag.String("difficulty", "", "The difficulty of the puzzle, or a range of them (e.g. hard or medium-expert)")
+	cluesPtr := flag.String("clues", "", "The number of clues of the puzzle, or a range of them (e.g. 25-30); used with -difficulty")
+	solverPtr := flag.String("solver", "backtracking", "The algorithm that solves the puzzles (backtracking or dlx)")
+	symmetryPtr := flag.String("symmetry", "rotational", "The symmetry of the clues (none, rotational, rotational-90, horizontal, vertical, diagonal, dihedral or custom)")
+	symmetryMaskPtr := flag.String("symmetry-mask", "", "The groups of cells that are emptied together, row by row, for the custom symmetry (e.g. \"ab..ba...\")")
+	maskPtr := flag.String("mask", "", "The cells of the clues, row by row, as a string or a file (e.g. \"x...x..x.\"; '.' is empty)")
+	minimalPtr := flag.Bool("minimal", false, "Whether to empty every clue that isn't needed, even if it breaks the symmetry")
+	formatPtr := flag.String("format", "box", "The order of the cells in the puzzle strings (box: box by box, row: row by row, candidates: a grid with the candidates of every cell)")
+	countPtr := flag.Int("count", 1, "The number of puzzles to generate, with seeds derived from -seed")
+	ndjsonPtr := flag.String("ndjson", "", "Writes the generated puzzles as JSON lines to a file, or to stdout if it's \"-\", instead of showing them")
+	workersPtr := flag.Int("workers", 0, "The number of puzzles generated at once along with -count; defaults to the number of CPUs")
+	attemptsPtr := flag.Int("attempts", sudoku.DefaultMaxAttempts, "The number of puzzles to try when generating to a difficulty or a mask")
 	flag.Parse()
 
-	var err error
+	if *schemaPtr {
+		fmt.Print(sudoku.DocumentSchema)
+		return
+	}
 
-	if *solvePtr != "" {
-		board, err := sudoku.ParseBoard(*solvePtr)
+	solver, err := sudoku.ParseSolver(*solverPtr)
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	format, err := sudoku.ParseFormat(*formatPtr)
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if *solvePtr != "" || *loadPtr != "" {
+		boxWidth, boxHeight, err := parseBoxSize(*boxPtr)
 
 		if err != nil {
 			fmt.Println(err)
+			return
+		}
+
+		var board *sudoku.Sudoku
+
+		if *loadPtr != "" {
+			board, err = sudoku.Load(*loadPtr)
+		} else {
+			board, err = sudoku.ParseBoardFormat(*solvePtr, format, boxWidth, boxHeight)
+		}
+
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		board.Solver = solver
+
+		// The JSON trace is printed by itself, so that it can be piped to other programs.
+		if *tracePtr == "json" {
+			traceJson, err := json.MarshalIndent(board.Trace(true), "", "  ")
+
+			if err != nil {
+				fmt.Println(err)
+			} else {
+				fmt.Println(string(traceJson))
+			}
+
+			return
+		} else if *tracePtr != "" && *tracePtr != "text" {
+			fmt.Printf("unknown trace format \"%s\"\n", *tracePtr)
+			return
 		}
 
 		board.Print(true)
 
+		if *tracePtr == "text" {
+			fmt.Print(board.Trace(true))
+		}
+
+		if *hintPtr > 0 {
+			hint, err := board.Hint()
+
+			if err != nil {
+				fmt.Println(err)
+			} else {
+				fmt.Println("Hint:", hint.Text(sudoku.HintLevel(*hintPtr)))
+			}
+
+			return
+		}
+
 		if *saveImgPtr {
 			err = createAndSaveImage(board, false)
 
@@ -51,41 +152,244 @@ func main() {
 		}
 
 		numOfSolutions := board.CountSolutions()
+		rating, err := board.Grade()
 		board.Solve()
 		board.Print(true)
 
 		fmt.Println("Possible solutions:", numOfSolutions)
 
+		if err == nil {
+			fmt.Println("Difficulty:", rating)
+		}
+
+		return
+	}
+
+	if err = sudoku.ValidateSize(*sizePtr); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	boxWidth, boxHeight, err := parseBoxSize(*boxPtr)
+
+	if err == nil && *boxPtr != "" {
+		err = sudoku.ValidateBoxSize(*sizePtr, boxWidth, boxHeight)
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	symmetry, err := sudoku.ParseSymmetry(*symmetryPtr)
+
+	if err == nil && *symmetryMaskPtr != "" {
+		symmetry = sudoku.CustomSymmetry
+	}
+
+	if err == nil && symmetry == sudoku.CustomSymmetry {
+		err = sudoku.ValidateSymmetryMask(*sizePtr, *symmetryMaskPtr)
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	mask := *maskPtr
+
+	// The mask can also be read from a file, which is easier to draw shapes in.
+	if maskFile, err := os.ReadFile(mask); err == nil {
+		mask = string(maskFile)
+	}
+
+	if mask != "" && (*difficultyPtr != "" || *minimalPtr) {
+		fmt.Println("-mask can't be used along with -difficulty or -minimal")
+		return
+	}
+
+	var opts *sudoku.GenerateOptions
+
+	if *difficultyPtr != "" {
+		opts, err = sudoku.ParseGenerateOptions(*difficultyPtr, *cluesPtr)
+
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		opts.MaxAttempts = *attemptsPtr
+		opts.Minimal = *minimalPtr
+	}
+
+	if *countPtr > 1 && *outputPtr != "" && *outputPtr != "@seed" {
+		fmt.Println("-output has to be @seed along with -count, so that every puzzle gets its own file")
 		return
 	}
 
-	fmt.Println("Seed:", *seedPtr)
+	// generate generates a puzzle out of a board, the way the flags ask for.
+	generate := func(board *sudoku.Sudoku) (*sudoku.Sudoku, error) {
+		if opts != nil {
+			return board.GenerateWithOptions(*opts)
+		} else if mask != "" {
+			return board.GenerateFromMask(mask, *attemptsPtr)
+		}
+
+		board.Fill()
+		puzzle := board.GeneratePuzzle()
+
+		if *minimalPtr {
+			if err := puzzle.Minimize(); err != nil {
+				return nil, err
+			}
+		}
+
+		return puzzle, nil
+	}
+
+	board := sudoku.Sudoku{
+		N:         uint8(*sizePtr),
+		BoxWidth:  uint8(boxWidth),
+		BoxHeight: uint8(boxHeight),
+		Seed:      *seedPtr,
+		Solver:    solver,
 
-	board := sudoku.Sudoku{Seed: *seedPtr}
+		Symmetry:     symmetry,
+		SymmetryMask: *symmetryMaskPtr,
+	}
 	board.Init()
 
+	out := outputOptions{
+		simple:          *simpleOutputPtr,
+		output:          *outputPtr,
+		format:          format,
+		saveImg:         *saveImgPtr,
+		saveSolutionImg: *saveSolutionImgPtr,
+	}
+
+	if *ndjsonPtr == "-" {
+		out.ndjson = json.NewEncoder(os.Stdout)
+	} else if *ndjsonPtr != "" {
+		f, err := os.Create(*ndjsonPtr)
+
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		defer f.Close()
+		out.ndjson = json.NewEncoder(f)
+	}
+
+	if *countPtr > 1 {
+		results := board.GenerateBatch(sudoku.BatchOptions{
+			Count:    *countPtr,
+			Workers:  *workersPtr,
+			Ordered:  true,
+			Generate: generate,
+		})
+
+		for result := range results {
+			if result.Err != nil {
+				out.printError(fmt.Errorf("seed %d: %w", result.Seed, result.Err))
+				continue
+			}
+
+			if out.ndjson == nil {
+				fmt.Println("Seed:", result.Seed)
+			}
+
+			showPuzzle(result.Solution, result.Puzzle, result.Duration, out)
+		}
+
+		return
+	}
+
+	if out.ndjson == nil {
+		fmt.Println("Seed:", *seedPtr)
+	}
+
 	start := time.Now()
-	board.Fill()
-	puzzle := board.GeneratePuzzle()
+	puzzle, err := generate(&board)
+
+	if err != nil {
+		out.printError(err)
+		return
+	}
 
 	// Here we measure the time it took to run the sudokugeneration algorithm.
 	duration := time.Since(start)
 
-	board.Print(!*simpleOutputPtr)
-	puzzle.Print(!*simpleOutputPtr)
+	showPuzzle(&board, puzzle, duration, out)
+}
 
-	if *outputPtr != "" {
-		err = board.Save(*outputPtr)
-	}
+// outputOptions holds the flags that decide how the generated puzzles are shown and saved.
+type outputOptions struct {
+	simple          bool
+	output          string
+	format          sudoku.Format
+	saveImg         bool
+	saveSolutionImg bool
 
-	if err != nil {
+	// ndjson writes the puzzles as JSON lines, instead of showing them, if it's set.
+	ndjson *json.Encoder
+}
+
+// printError prints an error. The JSON lines may be written to stdout, so it goes to stderr
+// along with them.
+func (out outputOptions) printError(err error) {
+	if out.ndjson != nil {
+		fmt.Fprintln(os.Stderr, err)
+	} else {
 		fmt.Println(err)
 	}
+}
+
+// showPuzzle prints a generated puzzle along with its solution, and saves them if the flags ask
+// for it.
+func showPuzzle(board, puzzle *sudoku.Sudoku, duration time.Duration, out outputOptions) {
+	var err error
+
+	// The rating is saved along with the board, so that puzzles can be sorted by it.
+	if puzzle.Rating == nil {
+		board.Rating, err = puzzle.Grade()
+		puzzle.Rating = board.Rating
+
+		if err != nil {
+			out.printError(err)
+		}
+	}
+
+	doc := sudoku.NewDocument(puzzle, board)
+	doc.GenerationTime = float64(duration.Microseconds()) / 1000
+
+	if out.output != "" {
+		if err = doc.Save(out.output); err != nil {
+			out.printError(err)
+		}
+	}
+
+	if out.ndjson != nil {
+		if err = out.ndjson.Encode(doc); err != nil {
+			out.printError(err)
+		}
+
+		saveImages(board, puzzle, out)
+
+		return
+	}
+
+	board.Print(!out.simple)
+	puzzle.Print(!out.simple)
 
 	ms := duration.Milliseconds()
 
 	fmt.Println("Puzzle string:")
-	fmt.Println(puzzle.String())
+	fmt.Println(puzzle.Format(out.format))
+
+	if puzzle.Rating != nil {
+		fmt.Println("Difficulty:", puzzle.Rating)
+	}
 
 	fmt.Print("Execution time: ")
 
@@ -95,27 +399,49 @@ func main() {
 		fmt.Printf("0.%dms\n", duration.Microseconds())
 	}
 
-	if *saveImgPtr {
-		err = createAndSaveImage(puzzle, false)
+	saveImages(board, puzzle, out)
+}
 
-		if err != nil {
-			fmt.Println(err)
-		} else {
+// saveImages saves the images of a puzzle and of its solution, if the flags ask for them.
+func saveImages(board, puzzle *sudoku.Sudoku, out outputOptions) {
+	if out.saveImg {
+		if err := createAndSaveImage(puzzle, false); err != nil {
+			out.printError(err)
+		} else if out.ndjson == nil {
 			fmt.Println("Saved the printable image of the sudoku puzzle")
 		}
 	}
 
-	if *saveSolutionImgPtr {
-		err = createAndSaveImage(&board, true)
-
-		if err != nil {
-			fmt.Println(err)
+	if out.saveSolutionImg {
+		if err := createAndSaveImage(board, true); err != nil {
+			out.printError(err)
 		}
 	}
 }
 
+// parseBoxSize parses a box size in the WxH format. An empty string gives 0x0, which stands
+// for the default size.
+func parseBoxSize(boxSize string) (int, int, error) {
+	width, height := 0, 0
+
+	if boxSize == "" {
+		return width, height, nil
+	}
+
+	if _, err := fmt.Sscanf(boxSize, "%dx%d", &width, &height); err != nil {
+		return 0, 0, fmt.Errorf("invalid box size \"%s\"", boxSize)
+	}
+
+	return width, height, nil
+}
+
 func createAndSaveImage(puzzle *sudoku.Sudoku, isSolution bool) error {
-	img, _ := image.CreateImage(puzzle)
+	img, err := image.CreateImage(puzzle)
+
+	if err != nil {
+		return err
+	}
+
 	label := ""
 
 	if isSolution {
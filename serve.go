@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/wisepythagoras/go-sudoku-gen/server"
+)
+
+// runServe serves the HTTP API of the server package until it fails.
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addrPtr := flags.String("addr", ":8080", "The address to listen on")
+	timeoutPtr := flags.Duration("timeout", server.DefaultTimeout, "How long a request can take")
+	flags.Parse(args)
+
+	srv := &http.Server{
+		Addr:              *addrPtr,
+		Handler:           server.New(server.Options{Timeout: *timeoutPtr, DailySalt: os.Getenv(dailySaltEnv)}),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       *timeoutPtr,
+
+		// The handler times out by itself, so this only covers writing its response.
+		WriteTimeout: *timeoutPtr + 5*time.Second,
+	}
+
+	fmt.Println("Listening on", *addrPtr)
+
+	return srv.ListenAndServe()
+}
@@ -0,0 +1,188 @@
+package server
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+// dailyPastDays is how many days back the daily puzzles are served. dailyFutureDays is how many
+// days ahead they are, since the day starts earlier in the timezones ahead of UTC. The puzzles
+// of the rest of the coming days are kept secret.
+const (
+	dailyPastDays   = 30
+	dailyFutureDays = 1
+)
+
+// dailyCacheSize is the number of daily puzzles that are kept, which is enough for every
+// difficulty of every date that's served.
+const dailyCacheSize = (dailyPastDays + dailyFutureDays + 1) * int(sudoku.Extreme+1)
+
+// dailyHandler serves the daily puzzles. They never change, so the most recently used ones are
+// kept once they're generated.
+type dailyHandler struct {
+	salt    string
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // The entries, from the most recently used one to the least.
+}
+
+// dailyEntry is a daily puzzle of the cache. `ready` is closed once the puzzle is generated, so
+// that the requests for a puzzle which is being generated wait for it instead of generating it
+// again.
+type dailyEntry struct {
+	key   string
+	ready chan struct{}
+	doc   *sudoku.Document
+	err   error
+}
+
+// newDailyHandler creates the handler of the daily puzzles of a salt.
+func newDailyHandler(salt string) *dailyHandler {
+	return &dailyHandler{salt: salt, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+func (h *dailyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s only accepts GET", r.URL.Path))
+		return
+	}
+
+	if h.salt == "" {
+		writeError(w, http.StatusNotFound, errors.New("the daily puzzles aren't enabled on this server"))
+		return
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	dateStr := strings.TrimPrefix(r.URL.Path, "/daily/")
+	date, err := time.Parse(sudoku.DateFormat, dateStr)
+
+	if dateStr == "today" {
+		date, err = today, nil
+	}
+
+	if err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("invalid date \"%s\", which should be like 2024-01-31", dateStr))
+		return
+	}
+
+	first, last := today.AddDate(0, 0, -dailyPastDays), today.AddDate(0, 0, dailyFutureDays)
+
+	if date.Before(first) || date.After(last) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("the daily puzzles are only served from %s to %s", first.Format(sudoku.DateFormat), last.Format(sudoku.DateFormat)))
+		return
+	}
+
+	difficulties, err := sudoku.ParseDailyDifficulties(r.URL.Query().Get("difficulty"))
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res := DailyResponse{Date: date.Format(sudoku.DateFormat), Puzzles: make([]*sudoku.Document, 0, len(difficulties))}
+
+	for _, difficulty := range difficulties {
+		doc, err := h.puzzle(r.Context(), date, difficulty)
+
+		if err != nil {
+			writeError(w, errorStatus(err), err)
+			return
+		}
+
+		res.Puzzles = append(res.Puzzles, doc)
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+// puzzle returns the document of the daily puzzle of a date and a difficulty, until the context
+// is done.
+func (h *dailyHandler) puzzle(ctx context.Context, date time.Time, difficulty sudoku.Difficulty) (*sudoku.Document, error) {
+	key := date.Format(sudoku.DateFormat) + "/" + difficulty.String()
+	var abort *sudoku.AbortError
+
+	for {
+		entry, generate := h.entry(key)
+
+		if generate {
+			h.generate(ctx, entry, date, difficulty)
+		}
+
+		select {
+		case <-entry.ready:
+		case <-ctx.Done():
+			return nil, &sudoku.AbortError{Err: ctx.Err()}
+		}
+
+		// A puzzle whose generation was stopped by the context of another request is generated
+		// again.
+		if entry.err == nil || generate || !errors.As(entry.err, &abort) {
+			return entry.doc, entry.err
+		}
+	}
+}
+
+// entry returns the entry of a key from the cache. If there isn't one, it adds it, evicting the
+// least recently used entry if the cache is full, and returns true, in which case the puzzle of
+// the entry has to be generated.
+func (h *dailyHandler) entry(key string) (*dailyEntry, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if elem, ok := h.entries[key]; ok {
+		h.order.MoveToFront(elem)
+
+		return elem.Value.(*dailyEntry), false
+	}
+
+	entry := &dailyEntry{key: key, ready: make(chan struct{})}
+	h.entries[key] = h.order.PushFront(entry)
+
+	if h.order.Len() > dailyCacheSize {
+		oldest := h.order.Back()
+		h.order.Remove(oldest)
+		delete(h.entries, oldest.Value.(*dailyEntry).key)
+	}
+
+	return entry, true
+}
+
+// generate generates the puzzle of an entry, until the context is done. The entries that were
+// stopped are taken out of the cache, so that the next request generates them again.
+func (h *dailyHandler) generate(ctx context.Context, entry *dailyEntry, date time.Time, difficulty sudoku.Difficulty) {
+	defer close(entry.ready)
+
+	start := time.Now()
+	puzzle, solution, err := sudoku.DailyPuzzleContext(ctx, date, h.salt, difficulty)
+
+	if err == nil {
+		entry.doc = sudoku.NewDocument(puzzle, solution)
+		entry.doc.GenerationTime = float64(time.Since(start).Microseconds()) / 1000
+
+		return
+	}
+
+	entry.err = err
+	var abort *sudoku.AbortError
+
+	if !errors.As(err, &abort) {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if elem, ok := h.entries[entry.key]; ok && elem.Value == entry {
+		h.order.Remove(elem)
+		delete(h.entries, entry.key)
+	}
+}
@@ -0,0 +1,514 @@
+// Package server exposes the generator, the solver and the printable images over HTTP, with JSON
+// requests and responses.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/png"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wisepythagoras/go-sudoku-gen/image"
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+// DefaultTimeout is how long a request can take when the options don't set it.
+const DefaultTimeout = 10 * time.Second
+
+// maxBodySize is the biggest request body that's read, which is plenty for a 25x25 board.
+const maxBodySize = 1 << 20
+
+// maxAttempts is the most puzzles a single request can try to generate.
+const maxAttempts = 1000
+
+// maxBoards is the most solutions a single request can get back.
+const maxBoards = 1000
+
+// Options holds the settings of the server.
+type Options struct {
+	Timeout time.Duration // How long a request can take; `DefaultTimeout` if 0.
+
+	// DailySalt is the secret salt of the daily puzzles (see `sudoku.DailySeed`). Their route
+	// is disabled if it's left empty.
+	DailySalt string
+}
+
+// Error is the body of every response that failed, like:
+//
+//	{"error": {"status": 400, "message": "unknown difficulty \"hardest\""}}
+type Error struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+// errorResponse wraps an error, so that it can't be confused with a successful response.
+type errorResponse struct {
+	Error Error `json:"error"`
+}
+
+// GenerateRequest is the body of "POST /generate". Every field is optional.
+type GenerateRequest struct {
+	Seed         *int64 `json:"seed"`          // The current time if it's left empty.
+	Size         int    `json:"size"`          // The number of rows and columns; 9 if 0.
+	Box          string `json:"box"`           // The size of the boxes as WxH (e.g. "3x2").
+	Difficulty   string `json:"difficulty"`    // A difficulty or a range of them (e.g. "medium-expert").
+	Clues        string `json:"clues"`         // A number of clues or a range of them (e.g. "25-30").
+	Symmetry     string `json:"symmetry"`      // The name of the symmetry (e.g. "diagonal").
+	SymmetryMask string `json:"symmetry_mask"` // The groups of cells of the custom symmetry.
+	Mask         string `json:"mask"`          // The cells of the clues, row by row (see `GenerateFromMask`).
+	Minimal      bool   `json:"minimal"`       // Whether to empty every clue that isn't needed.
+	Attempts     int    `json:"attempts"`      // The number of puzzles to try for a difficulty or a mask.
+}
+
+// BoardRequest is the body of "POST /solve", and part of the one of "POST /count-solutions".
+type BoardRequest struct {
+	Puzzle string `json:"puzzle"`
+	Format string `json:"format"` // The format of the puzzle (box, row or candidates); "row" if left empty.
+	Box    string `json:"box"`    // The size of the boxes as WxH; derived from the size of the puzzle if left empty.
+	Solver string `json:"solver"` // The algorithm that solves the puzzle (backtracking or dlx).
+}
+
+// CountRequest is the body of "POST /count-solutions".
+type CountRequest struct {
+	BoardRequest
+	Limit  int64 `json:"limit"`  // The number of solutions to stop counting at; 0 to count all of them.
+	Boards bool  `json:"boards"` // Whether to return the solutions, which needs a limit.
+}
+
+// SolveResponse is the body of a successful "POST /solve". The boards are in the format of the
+// request.
+type SolveResponse struct {
+	Puzzle   string         `json:"puzzle"`
+	Solution string         `json:"solution"`
+	Rating   *sudoku.Rating `json:"rating,omitempty"`
+}
+
+// DailyResponse is the body of a successful "GET /daily/{date}".
+type DailyResponse struct {
+	Date    string             `json:"date"`
+	Puzzles []*sudoku.Document `json:"puzzles"`
+}
+
+// CountResponse is the body of a successful "POST /count-solutions". If the count stopped at the
+// limit of the request, there may be more solutions. The boards are in the format of the request.
+type CountResponse struct {
+	Solutions    int64    `json:"solutions"`
+	Unique       bool     `json:"unique"`
+	LimitReached bool     `json:"limit_reached"`
+	Boards       []string `json:"boards,omitempty"`
+}
+
+// New returns the handler of the server, with the routes:
+//
+//	POST /generate          generates a puzzle (see `GenerateRequest`) and returns its document.
+//	POST /solve             solves a puzzle (see `BoardRequest` and `SolveResponse`).
+//	POST /count-solutions   counts the solutions of a puzzle (see `CountRequest` and `CountResponse`).
+//	GET  /puzzle/{seed}.png renders the puzzle of a seed, with the fields of `GenerateRequest`
+//	                        as query parameters, or its solution with "?solution=true".
+//	GET  /daily/{date}      returns the daily puzzles of a date, as YYYY-MM-DD or "today", or
+//	                        only some of them with "?difficulty=easy,hard" (see `DailyResponse`).
+//
+// Requests that take longer than the timeout get a 503 error.
+func New(opts Options) http.Handler {
+	timeout := opts.Timeout
+
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/generate", post(handleGenerate))
+	mux.HandleFunc("/solve", post(handleSolve))
+	mux.HandleFunc("/count-solutions", post(handleCountSolutions))
+	mux.HandleFunc("/puzzle/", handlePuzzleImage)
+	mux.Handle("/daily/", newDailyHandler(opts.DailySalt))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no route for %s", r.URL.Path))
+	})
+
+	timeoutBody, _ := json.Marshal(errorResponse{Error{
+		Status:  http.StatusServiceUnavailable,
+		Message: fmt.Sprintf("the request took longer than %s", timeout),
+	}})
+
+	return http.TimeoutHandler(mux, timeout, string(timeoutBody))
+}
+
+// post only lets POST requests through to a handler.
+func post(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s only accepts POST", r.URL.Path))
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+func handleGenerate(w http.ResponseWriter, r *http.Request) {
+	var req GenerateRequest
+
+	if !readRequest(w, r, &req) {
+		return
+	}
+
+	start := time.Now()
+	board, puzzle, err := generate(r.Context(), req)
+
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	doc := sudoku.NewDocument(puzzle, board)
+	doc.GenerationTime = float64(time.Since(start).Microseconds()) / 1000
+
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func handleSolve(w http.ResponseWriter, r *http.Request) {
+	var req BoardRequest
+
+	if !readRequest(w, r, &req) {
+		return
+	}
+
+	board, format, err := parseBoard(req)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	res := SolveResponse{Puzzle: board.Format(format)}
+
+	solutions, multiple, err := board.CountSolutionsUpToContext(r.Context(), 2)
+
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	if solutions == 0 {
+		writeError(w, http.StatusUnprocessableEntity, sudoku.ErrNoSolution)
+		return
+	}
+
+	// The rating is left out of puzzles that have more than one solution, since it would only
+	// describe one of them.
+	if !multiple {
+		if res.Rating, err = board.GradeContext(r.Context()); err != nil {
+			writeError(w, errorStatus(err), err)
+			return
+		}
+	}
+
+	if _, err := board.SolveContext(r.Context()); err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	res.Solution = board.Format(format)
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func handleCountSolutions(w http.ResponseWriter, r *http.Request) {
+	var req CountRequest
+
+	if !readRequest(w, r, &req) {
+		return
+	}
+
+	if req.Limit < 0 || (req.Boards && (req.Limit == 0 || req.Limit > maxBoards)) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("the limit has to be between 1 and %d to return the solutions", maxBoards))
+		return
+	}
+
+	board, format, err := parseBoard(req.BoardRequest)
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var res CountResponse
+
+	if req.Boards {
+		var solutions []*sudoku.Sudoku
+		solutions, res.LimitReached, err = board.SolutionsUpToContext(r.Context(), req.Limit)
+
+		for _, solution := range solutions {
+			res.Boards = append(res.Boards, solution.Format(format))
+		}
+
+		res.Solutions = int64(len(solutions))
+	} else {
+		res.Solutions, res.LimitReached, err = board.CountSolutionsUpToContext(r.Context(), req.Limit)
+	}
+
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	res.Unique = res.Solutions == 1 && !res.LimitReached
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func handlePuzzleImage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s only accepts GET", r.URL.Path))
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/puzzle/")
+	seedStr, found := strings.CutSuffix(name, ".png")
+	seed, err := strconv.ParseInt(seedStr, 10, 64)
+
+	if !found || err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("invalid image \"%s\", which should be like 42.png", name))
+		return
+	}
+
+	query := r.URL.Query()
+	req := GenerateRequest{
+		Seed:         &seed,
+		Box:          query.Get("box"),
+		Difficulty:   query.Get("difficulty"),
+		Clues:        query.Get("clues"),
+		Symmetry:     query.Get("symmetry"),
+		SymmetryMask: query.Get("symmetry_mask"),
+		Mask:         query.Get("mask"),
+		Minimal:      query.Get("minimal") == "true",
+	}
+
+	if size := query.Get("size"); size != "" {
+		if req.Size, err = strconv.Atoi(size); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid size \"%s\"", size))
+			return
+		}
+	}
+
+	board, puzzle, err := generate(r.Context(), req)
+
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	if query.Get("solution") == "true" {
+		puzzle = board
+	}
+
+	img, err := image.CreateImage(puzzle)
+
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	png.Encode(w, img)
+}
+
+// generate generates the puzzle of a request, until the context is done. It returns the solution
+// along with the puzzle.
+func generate(ctx context.Context, req GenerateRequest) (*sudoku.Sudoku, *sudoku.Sudoku, error) {
+	board := &sudoku.Sudoku{N: 9}
+
+	if req.Seed != nil {
+		board.Seed = *req.Seed
+	} else {
+		board.Seed = time.Now().UnixNano()
+	}
+
+	if req.Size != 0 {
+		if err := sudoku.ValidateSize(req.Size); err != nil {
+			return nil, nil, err
+		}
+
+		board.N = uint8(req.Size)
+	}
+
+	if req.Box != "" {
+		width, height, err := parseBoxSize(req.Box, int(board.N))
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		board.BoxWidth, board.BoxHeight = uint8(width), uint8(height)
+	}
+
+	if req.Symmetry != "" {
+		symmetry, err := sudoku.ParseSymmetry(req.Symmetry)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		board.Symmetry = symmetry
+	}
+
+	if req.SymmetryMask != "" {
+		board.Symmetry = sudoku.CustomSymmetry
+		board.SymmetryMask = req.SymmetryMask
+	}
+
+	if board.Symmetry == sudoku.CustomSymmetry {
+		if err := sudoku.ValidateSymmetryMask(int(board.N), board.SymmetryMask); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if req.Attempts < 0 || req.Attempts > maxAttempts {
+		return nil, nil, fmt.Errorf("the attempts have to be between 0 and %d", maxAttempts)
+	}
+
+	if req.Mask != "" && (req.Difficulty != "" || req.Minimal) {
+		return nil, nil, errors.New("a mask can't be used along with a difficulty or minimal")
+	}
+
+	board.Init()
+	var puzzle *sudoku.Sudoku
+	var err error
+
+	if req.Mask != "" {
+		puzzle, err = board.GenerateFromMaskContext(ctx, req.Mask, req.Attempts)
+	} else if req.Difficulty != "" {
+		var opts *sudoku.GenerateOptions
+
+		if opts, err = sudoku.ParseGenerateOptions(req.Difficulty, req.Clues); err != nil {
+			return nil, nil, err
+		}
+
+		opts.MaxAttempts = req.Attempts
+		opts.Minimal = req.Minimal
+		puzzle, err = board.GenerateWithOptionsContext(ctx, *opts)
+	} else if err = board.FillContext(ctx); err == nil {
+		puzzle, err = board.GeneratePuzzleContext(ctx)
+
+		if err == nil && req.Minimal {
+			err = puzzle.MinimizeContext(ctx)
+		}
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The rating is part of the document, so that puzzles can be sorted by it.
+	if puzzle.Rating == nil {
+		board.Rating, err = puzzle.GradeContext(ctx)
+		puzzle.Rating = board.Rating
+	}
+
+	return board, puzzle, err
+}
+
+// parseBoard parses the puzzle of a request, along with the format it's in.
+func parseBoard(req BoardRequest) (*sudoku.Sudoku, sudoku.Format, error) {
+	format := sudoku.RowMajor
+
+	if req.Format != "" {
+		var err error
+
+		if format, err = sudoku.ParseFormat(req.Format); err != nil {
+			return nil, format, err
+		}
+	}
+
+	width, height := 0, 0
+
+	if req.Box != "" {
+		var err error
+
+		if width, height, err = parseBoxSize(req.Box, 0); err != nil {
+			return nil, format, err
+		}
+	}
+
+	board, err := sudoku.ParseBoardFormat(req.Puzzle, format, width, height)
+
+	if err != nil {
+		return nil, format, err
+	}
+
+	if req.Solver != "" {
+		if board.Solver, err = sudoku.ParseSolver(req.Solver); err != nil {
+			return nil, format, err
+		}
+	}
+
+	return board, format, nil
+}
+
+// parseBoxSize parses a box size in the WxH format, and validates it if the size of the board
+// is known.
+func parseBoxSize(boxSize string, n int) (int, int, error) {
+	width, height := 0, 0
+
+	if _, err := fmt.Sscanf(boxSize, "%dx%d", &width, &height); err != nil {
+		return 0, 0, fmt.Errorf("invalid box size \"%s\"", boxSize)
+	}
+
+	if n != 0 {
+		if err := sudoku.ValidateBoxSize(n, width, height); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return width, height, nil
+}
+
+// errorStatus returns the status of an error of the generator or the solver. Running out of
+// attempts or of time isn't the fault of the request, unlike the rest.
+func errorStatus(err error) int {
+	if errors.Is(err, sudoku.ErrAttemptsExhausted) {
+		return http.StatusUnprocessableEntity
+	}
+
+	var abort *sudoku.AbortError
+
+	if errors.As(err, &abort) {
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusBadRequest
+}
+
+// readRequest reads the JSON body of a request. It writes the error and returns false if the
+// body is invalid.
+func readRequest(w http.ResponseWriter, r *http.Request, req any) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return false
+	}
+
+	return true
+}
+
+// writeJSON writes a response as JSON.
+func writeJSON(w http.ResponseWriter, status int, res any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(res)
+}
+
+// writeError writes an error as JSON (see `Error`).
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error{Status: status, Message: err.Error()}})
+}
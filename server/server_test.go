@@ -0,0 +1,234 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wisepythagoras/go-sudoku-gen/server"
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+const puzzle = "4.1..38......2....7....5..6......2.5.7..5..9.3.4......9..5....2....1......63..1.4"
+
+// request sends a request to the server and decodes its JSON response into `res`.
+func request(t *testing.T, method, path, body string, res any) int {
+	t.Helper()
+
+	handler := server.New(server.Options{})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+
+	if res != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), res); err != nil {
+			t.Fatalf("Invalid response %s: %v", rec.Body, err)
+		}
+	}
+
+	return rec.Code
+}
+
+func TestGenerate(t *testing.T) {
+	var doc sudoku.Document
+
+	if status := request(t, "POST", "/generate", `{"seed": 5, "size": 6, "symmetry": "none"}`, &doc); status != http.StatusOK {
+		t.Fatalf("Unexpected status %d", status)
+	}
+
+	if doc.Seed != 5 || doc.N != 6 || doc.Symmetry != sudoku.NoSymmetry || doc.Rating == nil || doc.Validate() != nil {
+		t.Errorf("Unexpected document %+v", doc)
+	}
+
+	// The same seed always leads to the same puzzle.
+	var again sudoku.Document
+	request(t, "POST", "/generate", `{"seed": 5, "size": 6, "symmetry": "none"}`, &again)
+
+	if again.Puzzle != doc.Puzzle {
+		t.Error("The same seed led to a different puzzle")
+	}
+
+	var res struct {
+		Error server.Error `json:"error"`
+	}
+
+	invalid := []string{`{"size": 7}`, `{"difficulty": "hardest"}`, `{"box": "2x2"}`, `{"seeds": 1}`, `{`}
+
+	for _, body := range invalid {
+		if status := request(t, "POST", "/generate", body, &res); status != http.StatusBadRequest || res.Error.Status != status {
+			t.Errorf("Expected a structured error for %s, got %d", body, status)
+		}
+	}
+
+	if status := request(t, "GET", "/generate", "", &res); status != http.StatusMethodNotAllowed {
+		t.Errorf("Expected GET to be rejected, got %d", status)
+	}
+}
+
+func TestSolve(t *testing.T) {
+	var res server.SolveResponse
+
+	if status := request(t, "POST", "/solve", `{"puzzle": "`+puzzle+`"}`, &res); status != http.StatusOK {
+		t.Fatalf("Unexpected status %d", status)
+	}
+
+	solution, _ := sudoku.ParseRowMajor(puzzle)
+	solution.Solve()
+
+	if res.Solution != solution.RowMajorString() || res.Rating == nil {
+		t.Errorf("Unexpected solution %s", res.Solution)
+	}
+
+	// The boards are returned in the format of the request.
+	request(t, "POST", "/solve", `{"puzzle": "`+solution.String()+`", "format": "box"}`, &res)
+
+	if res.Solution != solution.String() {
+		t.Errorf("Expected the box-major solution, got %s", res.Solution)
+	}
+
+	if status := request(t, "POST", "/solve", `{"puzzle": "1.1............."}`, nil); status != http.StatusUnprocessableEntity {
+		t.Errorf("Expected an unsolvable puzzle to fail, got %d", status)
+	}
+
+	if status := request(t, "POST", "/solve", `{"puzzle": "1"}`, nil); status != http.StatusBadRequest {
+		t.Errorf("Expected an invalid puzzle to fail, got %d", status)
+	}
+}
+
+func TestCountSolutions(t *testing.T) {
+	var res server.CountResponse
+
+	if status := request(t, "POST", "/count-solutions", `{"puzzle": "`+puzzle+`", "solver": "dlx"}`, &res); status != http.StatusOK {
+		t.Fatalf("Unexpected status %d", status)
+	}
+
+	if res.Solutions != 1 || !res.Unique {
+		t.Errorf("Expected a single solution, got %d", res.Solutions)
+	}
+
+	request(t, "POST", "/count-solutions", `{"puzzle": "1..............."}`, &res)
+
+	if res.Solutions != 72 || res.Unique || res.LimitReached {
+		t.Errorf("Expected 72 solutions, got %d", res.Solutions)
+	}
+
+	res = server.CountResponse{}
+	request(t, "POST", "/count-solutions", `{"puzzle": "1...............", "limit": 10, "boards": true, "format": "box"}`, &res)
+
+	if res.Solutions != 10 || !res.LimitReached || len(res.Boards) != 10 || len(res.Boards[0]) != 16 {
+		t.Errorf("Expected 10 of the solutions, got %d", res.Solutions)
+	}
+
+	for _, body := range []string{`{"puzzle": "1...............", "boards": true}`, `{"puzzle": "1...............", "limit": -1}`} {
+		if status := request(t, "POST", "/count-solutions", body, nil); status != http.StatusBadRequest {
+			t.Errorf("Expected %s to be rejected, got %d", body, status)
+		}
+	}
+}
+
+func TestPuzzleImage(t *testing.T) {
+	handler := server.New(server.Options{})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/puzzle/42.png?size=4", nil))
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" || !strings.HasPrefix(rec.Body.String(), "\x89PNG") {
+		t.Errorf("Unexpected response %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	for _, path := range []string{"/puzzle/42", "/puzzle/abc.png", "/nothing"} {
+		if status := request(t, "GET", path, "", nil); status != http.StatusNotFound {
+			t.Errorf("Expected %s not to be found, got %d", path, status)
+		}
+	}
+}
+
+func TestDaily(t *testing.T) {
+	handler := server.New(server.Options{DailySalt: "salt"})
+	date := time.Now().UTC().AddDate(0, 0, -1)
+	path := "/daily/" + date.Format(sudoku.DateFormat) + "?difficulty=easy"
+
+	// The requests that come in while the puzzle is generated get the same one, without
+	// generating it again.
+	responses := make([]server.DailyResponse, 4)
+	var wg sync.WaitGroup
+
+	for i := range responses {
+		wg.Add(1)
+
+		go func(res *server.DailyResponse) {
+			defer wg.Done()
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+
+			if err := json.Unmarshal(rec.Body.Bytes(), res); err != nil || rec.Code != http.StatusOK {
+				t.Errorf("Unexpected response %d %s", rec.Code, rec.Body)
+			}
+		}(&responses[i])
+	}
+
+	wg.Wait()
+
+	if t.Failed() {
+		return
+	}
+
+	puzzle, _, _ := sudoku.DailyPuzzle(date, "salt", sudoku.Easy)
+	res := responses[0]
+
+	if res.Date != date.Format(sudoku.DateFormat) || len(res.Puzzles) != 1 || res.Puzzles[0].Puzzle != puzzle.RowMajorString() {
+		t.Errorf("Unexpected daily puzzles %+v", res)
+	}
+
+	for _, other := range responses[1:] {
+		if other.Puzzles[0].GenerationTime != res.Puzzles[0].GenerationTime {
+			t.Error("Expected the puzzle to be generated once")
+		}
+	}
+
+	// The difficulties can be separated by spaces too.
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", path+",%20medium", nil))
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil || rec.Code != http.StatusOK || len(res.Puzzles) != 2 {
+		t.Errorf("Unexpected response %d %s", rec.Code, rec.Body)
+	}
+
+	// Only the dates close to today are served, so that the coming puzzles are kept secret.
+	paths := []string{
+		"/daily/2024-02-30",
+		"/daily/yesterday",
+		"/daily/" + time.Now().UTC().AddDate(0, 0, -31).Format(sudoku.DateFormat),
+		"/daily/" + time.Now().UTC().AddDate(0, 0, 2).Format(sudoku.DateFormat),
+	}
+
+	for _, path := range paths {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected %s not to be found, got %d", path, rec.Code)
+		}
+	}
+
+	// The daily puzzles are disabled without a salt.
+	if status := request(t, "GET", "/daily/today", "", nil); status != http.StatusNotFound {
+		t.Errorf("Expected the daily puzzles to be disabled, got %d", status)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	// The empty board has far too many solutions to count, so the count has to stop at the
+	// timeout instead of running in the background.
+	handler := server.New(server.Options{Timeout: 50 * time.Millisecond})
+	rec := httptest.NewRecorder()
+	start := time.Now()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/count-solutions", strings.NewReader(`{"puzzle": "`+strings.Repeat(".", 81)+`"}`)))
+
+	if rec.Code != http.StatusServiceUnavailable || time.Since(start) > time.Second {
+		t.Errorf("Expected the count to time out, got %d after %s", rec.Code, time.Since(start))
+	}
+}
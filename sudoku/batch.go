@@ -0,0 +1,145 @@
+package sudoku
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// BatchOptions holds the settings of `GenerateBatch`.
+type BatchOptions struct {
+	Count   int  // The number of puzzles to generate.
+	Workers int  // The number of puzzles generated at once; the number of CPUs if 0.
+	Ordered bool // Whether the results are sent in the order of their index.
+
+	// Generate generates a puzzle out of a board that was initialized with its own seed, and
+	// leaves the board with the solution. It's `Fill` followed by `GeneratePuzzle` if left empty.
+	// It's called from many goroutines at once, so it must not share any boards between them.
+	Generate func(board *Sudoku) (*Sudoku, error)
+}
+
+// BatchResult is a puzzle of a batch, or the error that was returned while generating it.
+type BatchResult struct {
+	Index    int
+	Seed     int64
+	Puzzle   *Sudoku
+	Solution *Sudoku
+	Duration time.Duration // How long it took to generate the puzzle.
+	Err      error
+}
+
+// DeriveSeed returns the seed of the puzzle with an index in a batch, out of the seed of the
+// batch. The first puzzle keeps the seed of the batch, so that it's the same puzzle a single
+// run with that seed generates, while the rest get seeds that are spread apart from each other
+// (and from the seeds of the attempts of `GenerateWithOptions`, which follow each other).
+func DeriveSeed(seed int64, index int) int64 {
+	if index == 0 {
+		return seed
+	}
+
+	// This is SplitMix64, which turns consecutive numbers into well mixed ones.
+	z := uint64(seed) + uint64(index)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+
+	return int64(z ^ (z >> 31))
+}
+
+// GenerateBatch generates many puzzles at once, across a pool of workers. Each puzzle gets a
+// board like this one (with the same size, symmetry and solver), but with its own seed (see
+// `DeriveSeed`), so the puzzles only depend on the seed of this board and not on the number of
+// workers or on the order they finish in. The results are sent on the returned channel as soon
+// as they're ready, or as soon as all the ones before them are ready if `Ordered` is set, and the
+// channel is closed once all of them are sent. The channel has to be drained, since the workers
+// wait for their results to be received.
+func (s *Sudoku) GenerateBatch(opts BatchOptions) <-chan BatchResult {
+	workers := opts.Workers
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	if workers > opts.Count {
+		workers = opts.Count
+	}
+
+	indexes := make(chan int)
+	results := make(chan BatchResult)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for idx := range indexes {
+				results <- s.generateBatchPuzzle(idx, opts.Generate)
+			}
+		}()
+	}
+
+	go func() {
+		for idx := 0; idx < opts.Count; idx++ {
+			indexes <- idx
+		}
+
+		close(indexes)
+		wg.Wait()
+		close(results)
+	}()
+
+	if !opts.Ordered {
+		return results
+	}
+
+	ordered := make(chan BatchResult)
+
+	go func() {
+		pending := make(map[int]BatchResult)
+		next := 0
+
+		for result := range results {
+			pending[result.Index] = result
+
+			for r, ok := pending[next]; ok; r, ok = pending[next] {
+				delete(pending, next)
+				ordered <- r
+				next++
+			}
+		}
+
+		close(ordered)
+	}()
+
+	return ordered
+}
+
+// generateBatchPuzzle generates the puzzle with an index in a batch.
+func (s *Sudoku) generateBatchPuzzle(idx int, generate func(board *Sudoku) (*Sudoku, error)) BatchResult {
+	board := &Sudoku{
+		N:            s.N,
+		BoxWidth:     s.BoxWidth,
+		BoxHeight:    s.BoxHeight,
+		Seed:         DeriveSeed(s.Seed, idx),
+		Symmetry:     s.Symmetry,
+		SymmetryMask: s.SymmetryMask,
+		Solver:       s.Solver,
+		Constraints:  s.Constraints,
+	}
+	board.Init()
+
+	result := BatchResult{Index: idx, Seed: board.Seed, Solution: board}
+	start := time.Now()
+
+	if generate != nil {
+		result.Puzzle, result.Err = generate(board)
+	} else {
+		board.Fill()
+		result.Puzzle = board.GeneratePuzzle()
+	}
+
+	result.Duration = time.Since(start)
+
+	return result
+}
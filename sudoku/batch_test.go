@@ -0,0 +1,66 @@
+package sudoku_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+func TestGenerateBatch(t *testing.T) {
+	board := &sudoku.Sudoku{N: 6, Seed: 12, Symmetry: sudoku.NoSymmetry}
+	board.Init()
+
+	// The puzzles only depend on their seeds, so they're the same with any number of workers.
+	puzzles := make(map[int64]string)
+
+	for result := range board.GenerateBatch(sudoku.BatchOptions{Count: 8, Workers: 1}) {
+		if result.Err != nil {
+			t.Fatal(result.Err)
+		}
+
+		if result.Seed != sudoku.DeriveSeed(12, result.Index) || result.Puzzle.CountSolutions() != 1 {
+			t.Errorf("Unexpected puzzle %d with the seed %d", result.Index, result.Seed)
+		}
+
+		puzzles[result.Seed] = result.Puzzle.String()
+	}
+
+	next := 0
+
+	for result := range board.GenerateBatch(sudoku.BatchOptions{Count: 8, Workers: 4, Ordered: true}) {
+		if result.Index != next {
+			t.Errorf("Expected the result %d, got %d", next, result.Index)
+		}
+
+		if puzzles[result.Seed] != result.Puzzle.String() {
+			t.Errorf("The puzzle with the seed %d changed with more workers", result.Seed)
+		}
+
+		next++
+	}
+
+	if next != 8 || len(puzzles) != 8 {
+		t.Errorf("Expected 8 distinct puzzles, got %d and %d", next, len(puzzles))
+	}
+
+	// The first puzzle is the one a single run with the same seed generates.
+	single := &sudoku.Sudoku{N: 6, Seed: 12, Symmetry: sudoku.NoSymmetry}
+	single.Init()
+	single.Fill()
+
+	if puzzles[12] != single.GeneratePuzzle().String() {
+		t.Error("The first puzzle of the batch is not the one of its seed")
+	}
+
+	errFailed := errors.New("failed")
+	generate := func(board *sudoku.Sudoku) (*sudoku.Sudoku, error) {
+		return nil, errFailed
+	}
+
+	for result := range board.GenerateBatch(sudoku.BatchOptions{Count: 2, Generate: generate}) {
+		if result.Err != errFailed {
+			t.Errorf("Expected the error of the generator, got %v", result.Err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// The results of these benchmarks from when the boxes kept their numbers in maps, before the
+// solvers searched on a grid of bitmasks, are in testdata/benchmarks-before.txt. They were
+// recorded by running this file against that version, and the current results compare with
+// them with:
+//
+//	go test -run '^$' -bench . -benchmem -count 6 > /tmp/benchmarks-after.txt
+//	benchstat testdata/benchmarks-before.txt /tmp/benchmarks-after.txt
+//
+// The solvers and the fill don't allocate once their boards are set up, while the generation
+// only allocates the puzzle it returns and a couple of buffers of the size of the board.
+
+package sudoku_test
+
+import (
+	"testing"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+func BenchmarkSolve(b *testing.B) {
+	puzzle := initSudoku()
+	s := &sudoku.Sudoku{}
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		s.Copy(puzzle)
+		s.Solve()
+	}
+}
+
+func BenchmarkSolveHard(b *testing.B) {
+	puzzle := initRows(hardPuzzle)
+	s := &sudoku.Sudoku{}
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		s.Copy(puzzle)
+		s.Solve()
+	}
+}
+
+func BenchmarkCountSolutions(b *testing.B) {
+	s := initRows(hardPuzzle)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		s.CountSolutions()
+	}
+}
+
+func BenchmarkHasMultipleSolutions(b *testing.B) {
+	s := initSudoku()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		s.HasMultipleSolutions()
+	}
+}
+
+func BenchmarkFill(b *testing.B) {
+	s := &sudoku.Sudoku{Seed: 1}
+	s.Init()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		s.Fill()
+	}
+}
+
+func BenchmarkFill16(b *testing.B) {
+	s := &sudoku.Sudoku{N: 16, Seed: 1}
+	s.Init()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		s.Fill()
+	}
+}
+
+// The allocations of GeneratePuzzle are the ones of the puzzle it returns and of its buffers,
+// which don't depend on how many puzzles it goes through.
+func BenchmarkGeneratePuzzle(b *testing.B) {
+	s := &sudoku.Sudoku{Seed: 1}
+	s.Init()
+	s.Fill()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		s.GeneratePuzzle()
+	}
+}
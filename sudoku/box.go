@@ -1,65 +1,68 @@
 package sudoku
 
 import (
+	"encoding/json"
 	"fmt"
 )
 
 // Box defines the strucure of each box in a Sudoku puzzle.
 type Box struct {
-	N          uint8
-	numbers    []uint8
-	numbersMap map[uint8]uint8
+	N       uint8
+	Width   uint8 // The number of columns; derived from N if left empty.
+	Height  uint8 // The number of rows; derived from N if left empty.
+	numbers []uint8
+	mask    uint32 // The numbers in the box as bits.
 }
 
-// Init initializes the numbers array and numbers map. This needs to be
-// run before anything else runs.
+// Init initializes the numbers array. This needs to be run before anything else runs.
 func (b *Box) Init() {
-	b.numbers = make([]uint8, b.N)
-	b.numbersMap = make(map[uint8]uint8)
+	if b.Width == 0 || b.Height == 0 {
+		b.Width, b.Height = boxDimensions(b.N)
+	}
+
+	if len(b.numbers) != int(b.N) {
+		b.numbers = make([]uint8, b.N)
+	}
 
 	for i := range b.numbers {
 		b.numbers[i] = 0
 	}
+
+	b.mask = 0
 }
 
 // Has returns whether this box has a number.
 func (b *Box) Has(n uint8) bool {
-	if _, ok := b.numbersMap[n]; ok {
-		return true
-	}
-
-	return false
+	return n != 0 && b.mask&(1<<n) != 0
 }
 
 // RowHas returns whether a row has a number.
 func (b *Box) RowHas(r, n uint8) bool {
-	pos, ok := b.numbersMap[n]
-
-	if !ok {
-		return false
-	}
-
-	// TODO: This shouldn't be 2.
-	if pos-3*r <= 2 {
-		return true
-	}
+	pos := b.posOf(n)
 
-	return false
+	return pos >= 0 && pos/int(b.Width) == int(r)
 }
 
 // ColHas returns whether a column has a number.
 func (b *Box) ColHas(c, n uint8) bool {
-	pos, ok := b.numbersMap[n]
+	pos := b.posOf(n)
 
-	if !ok {
-		return false
+	return pos >= 0 && pos%int(b.Width) == int(c)
+}
+
+// posOf returns the position of a number in the box, or -1 if it's not there.
+func (b *Box) posOf(n uint8) int {
+	if !b.Has(n) {
+		return -1
 	}
 
-	if pos-c == 0 || pos-c == 3 || pos-c == 6 {
-		return true
+	for pos, num := range b.numbers {
+		if num == n {
+			return pos
+		}
 	}
 
-	return false
+	return -1
 }
 
 // GetPos returns the number in a specific absolute position of the box.
@@ -73,7 +76,7 @@ func (b *Box) GetPos(i int) uint8 {
 
 // Insert places a number in a specific cell.
 func (b *Box) Insert(c, r, n uint8) bool {
-	if c >= b.N/3 || r >= b.N/3 || n > b.N {
+	if c >= b.Width || r >= b.Height || n > b.N {
 		return false
 	}
 
@@ -81,16 +84,12 @@ func (b *Box) Insert(c, r, n uint8) bool {
 		return false
 	}
 
-	pos := c + r*3
-
-	if _, ok := b.numbersMap[b.numbers[pos]]; ok {
-		delete(b.numbersMap, b.numbers[pos])
-	}
-
+	pos := c + r*b.Width
+	b.mask &^= 1 << b.numbers[pos]
 	b.numbers[pos] = n
 
 	if n != 0 {
-		b.numbersMap[n] = pos
+		b.mask |= 1 << n
 	}
 
 	return true
@@ -106,37 +105,45 @@ func (b *Box) InsertPos(pos int, n uint8) bool {
 		return false
 	}
 
-	if _, ok := b.numbersMap[b.numbers[pos]]; ok {
-		delete(b.numbersMap, b.numbers[pos])
-	}
-
+	b.mask &^= 1 << b.numbers[pos]
 	b.numbers[pos] = n
-	b.numbersMap[n] = uint8(pos)
+
+	if n != 0 {
+		b.mask |= 1 << n
+	}
 
 	return true
 }
 
 // GetRow returns the numbers in a specific row.
 func (b *Box) GetRow(r int) []uint8 {
-	if r > 3 {
+	if r < 0 || r >= int(b.Height) {
 		return []uint8{}
 	}
 
-	return b.numbers[r*3 : r*3+3]
+	w := int(b.Width)
+
+	return b.numbers[r*w : r*w+w]
 }
 
-// GetCol returns the umbers in a specific column.
+// GetCol returns the numbers in a specific column.
 func (b *Box) GetCol(c int) []uint8 {
-	if c > 3 {
+	if c < 0 || c >= int(b.Width) {
 		return []uint8{}
 	}
 
-	return []uint8{b.numbers[c], b.numbers[c+3], b.numbers[c+6]}
+	col := make([]uint8, b.Height)
+
+	for i := range col {
+		col[i] = b.numbers[c+i*int(b.Width)]
+	}
+
+	return col
 }
 
 // GetNumbers returns a copy of the numbers in the box.
 func (b *Box) GetNumbers() []uint8 {
-	numbers := make([]uint8, 9)
+	numbers := make([]uint8, b.N)
 	copy(numbers, b.numbers)
 
 	return numbers
@@ -155,12 +162,23 @@ func (b *Box) CountEmpty() int {
 	return count
 }
 
-// SetNumbers sets the numbers.
+// SetNumbers sets the numbers. They're copied, so that the box doesn't share them with the
+// caller.
 func (b *Box) SetNumbers(numbers []uint8) {
-	b.numbers = numbers
+	if len(b.numbers) != int(b.N) {
+		b.numbers = make([]uint8, b.N)
+	}
+
+	for i := copy(b.numbers, numbers); i < len(b.numbers); i++ {
+		b.numbers[i] = 0
+	}
 
-	for i, num := range numbers {
-		b.numbersMap[num] = uint8(i)
+	b.mask = 0
+
+	for _, num := range b.numbers {
+		if num != 0 {
+			b.mask |= 1 << num
+		}
 	}
 }
 
@@ -185,3 +203,22 @@ func (b *Box) MarshalJSON() ([]byte, error) {
 
 	return []byte(array), nil
 }
+
+// UnmarshalJSON is used by the JSON module to read the numbers of the box from an array. Their
+// count becomes the size of the box, while its width and height are left to the board.
+func (b *Box) UnmarshalJSON(data []byte) error {
+	var numbers []uint8
+
+	if err := json.Unmarshal(data, &numbers); err != nil {
+		return err
+	}
+
+	if len(numbers) > MaxN {
+		return fmt.Errorf("a box can't have more than %d numbers, but it has %d", MaxN, len(numbers))
+	}
+
+	b.N = uint8(len(numbers))
+	b.SetNumbers(numbers)
+
+	return nil
+}
@@ -0,0 +1,238 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/bits"
+)
+
+// CandidateSet is a set of numbers, as bits, like the candidates or the pencil marks of a cell.
+type CandidateSet uint32
+
+// NewCandidateSet returns a set with the given numbers.
+func NewCandidateSet(numbers ...uint8) CandidateSet {
+	var set CandidateSet
+
+	for _, n := range numbers {
+		set = set.Add(n)
+	}
+
+	return set
+}
+
+// Has returns whether the set has a number.
+func (c CandidateSet) Has(n uint8) bool {
+	return n != 0 && n <= MaxN && c&(1<<n) != 0
+}
+
+// Add returns the set with a number added to it.
+func (c CandidateSet) Add(n uint8) CandidateSet {
+	if n == 0 || n > MaxN {
+		return c
+	}
+
+	return c | 1<<n
+}
+
+// Remove returns the set without a number.
+func (c CandidateSet) Remove(n uint8) CandidateSet {
+	if n == 0 || n > MaxN {
+		return c
+	}
+
+	return c &^ (1 << n)
+}
+
+// Count returns the number of numbers in the set.
+func (c CandidateSet) Count() int {
+	return bits.OnesCount32(uint32(c))
+}
+
+// Numbers returns the numbers in the set, in order.
+func (c CandidateSet) Numbers() []uint8 {
+	numbers := make([]uint8, 0, c.Count())
+
+	for set := uint32(c); set != 0; set &= set - 1 {
+		numbers = append(numbers, uint8(bits.TrailingZeros32(set)))
+	}
+
+	return numbers
+}
+
+// String returns the numbers in the set, like "3/7".
+func (c CandidateSet) String() string {
+	return joinNumbers(uint32(c))
+}
+
+// MarshalJSON is used by the JSON module to write the set as an array of numbers.
+func (c CandidateSet) MarshalJSON() ([]byte, error) {
+	// A slice of bytes would be written as a base64 string, so the numbers are widened first.
+	numbers := make([]int, 0, c.Count())
+
+	for _, n := range c.Numbers() {
+		numbers = append(numbers, int(n))
+	}
+
+	return json.Marshal(numbers)
+}
+
+// UnmarshalJSON is used by the JSON module to read the set from an array of numbers.
+func (c *CandidateSet) UnmarshalJSON(data []byte) error {
+	var numbers []int
+
+	if err := json.Unmarshal(data, &numbers); err != nil {
+		return err
+	}
+
+	*c = 0
+
+	for _, n := range numbers {
+		if n < 1 || n > MaxN {
+			return fmt.Errorf("invalid candidate %d", n)
+		}
+
+		*c = c.Add(uint8(n))
+	}
+
+	return nil
+}
+
+// Candidates returns the numbers that can go in a cell, based on the numbers of its row, column
+// and box, without the ones that were eliminated. A cell which has a number has no candidates.
+func (s *Sudoku) Candidates(row, col int) CandidateSet {
+	if !s.inBounds(row, col) || s.GetCell(row, col) != 0 {
+		return 0
+	}
+
+	// Only the row, the column and the box of the cell are looked at, rather than the board.
+	n := int(s.N)
+	box, _ := s.boxPosFromRowCol(row, col)
+	used := s.Board[box].mask
+
+	for i := 0; i < n; i++ {
+		used |= uint32(1)<<s.GetCell(row, i) | uint32(1)<<s.GetCell(i, col)
+	}
+
+	all := uint32(1<<(n+1)) - 2
+
+	return CandidateSet(all&^used) &^ s.eliminated(row*n+col)
+}
+
+// CandidateGrid returns the candidates of every cell, row by row (see `Candidates`).
+func (s *Sudoku) CandidateGrid() []CandidateSet {
+	g := newGrid(s)
+	defer g.release()
+	grid := make([]CandidateSet, len(g.cells))
+
+	for idx, num := range g.cells {
+		if num != 0 {
+			continue
+		}
+
+		grid[idx] = CandidateSet(g.possibilities(idx)) &^ s.eliminated(idx)
+	}
+
+	return grid
+}
+
+// EliminateCandidate rules out a number from the candidates of a cell. It returns false if the
+// cell or the number don't exist.
+func (s *Sudoku) EliminateCandidate(row, col int, n uint8) bool {
+	if !s.inBounds(row, col) || n == 0 || n > s.N {
+		return false
+	}
+
+	if len(s.Eliminations) != int(s.N)*int(s.N) {
+		s.Eliminations = make([]CandidateSet, int(s.N)*int(s.N))
+	}
+
+	idx := row*int(s.N) + col
+	s.Eliminations[idx] = s.Eliminations[idx].Add(n)
+
+	return true
+}
+
+// RestoreCandidate undoes the elimination of a number from the candidates of a cell. It returns
+// false if the cell or the number don't exist.
+func (s *Sudoku) RestoreCandidate(row, col int, n uint8) bool {
+	if !s.inBounds(row, col) || n == 0 || n > s.N {
+		return false
+	}
+
+	if idx := row*int(s.N) + col; idx < len(s.Eliminations) {
+		s.Eliminations[idx] = s.Eliminations[idx].Remove(n)
+	}
+
+	return true
+}
+
+// ResetCandidates restores all the candidates that were eliminated.
+func (s *Sudoku) ResetCandidates() {
+	s.Eliminations = nil
+}
+
+// PencilMarks returns the numbers that were noted in a cell.
+func (s *Sudoku) PencilMarks(row, col int) CandidateSet {
+	if idx := row*int(s.N) + col; s.inBounds(row, col) && idx < len(s.Notes) {
+		return s.Notes[idx]
+	}
+
+	return 0
+}
+
+// SetPencilMarks replaces the numbers that are noted in a cell. It returns false if the cell
+// doesn't exist or if the marks have numbers bigger than the board allows.
+func (s *Sudoku) SetPencilMarks(row, col int, marks CandidateSet) bool {
+	if !s.inBounds(row, col) || marks&^CandidateSet(uint32(1<<(s.N+1))-2) != 0 {
+		return false
+	}
+
+	if len(s.Notes) != int(s.N)*int(s.N) {
+		s.Notes = make([]CandidateSet, int(s.N)*int(s.N))
+	}
+
+	s.Notes[row*int(s.N)+col] = marks
+
+	return true
+}
+
+// TogglePencilMark notes a number in a cell, or removes it if it was already noted. It returns
+// false if the cell or the number don't exist.
+func (s *Sudoku) TogglePencilMark(row, col int, n uint8) bool {
+	if n == 0 || n > s.N {
+		return false
+	}
+
+	marks := s.PencilMarks(row, col)
+
+	if marks.Has(n) {
+		return s.SetPencilMarks(row, col, marks.Remove(n))
+	}
+
+	return s.SetPencilMarks(row, col, marks.Add(n))
+}
+
+// FillPencilMarks notes the candidates of every empty cell (see `CandidateGrid`), replacing the
+// existing notes.
+func (s *Sudoku) FillPencilMarks() {
+	s.Notes = s.CandidateGrid()
+}
+
+// ClearPencilMarks removes the notes of every cell.
+func (s *Sudoku) ClearPencilMarks() {
+	s.Notes = nil
+}
+
+// eliminated returns the candidates that were eliminated from the cell with an index.
+func (s *Sudoku) eliminated(idx int) CandidateSet {
+	if idx < len(s.Eliminations) {
+		return s.Eliminations[idx]
+	}
+
+	return 0
+}
+
+// inBounds returns whether a cell is on the board.
+func (s *Sudoku) inBounds(row, col int) bool {
+	return row >= 0 && col >= 0 && row < int(s.N) && col < int(s.N)
+}
@@ -0,0 +1,105 @@
+package sudoku_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+func TestCandidates(t *testing.T) {
+	s := initRows(hardPuzzle)
+
+	// The row of r1c2 has 1 and 2, its column 9, 5 and 3, and its box 1, 9 and 6.
+	if candidates := s.Candidates(0, 1); candidates != sudoku.NewCandidateSet(4, 7, 8) {
+		t.Errorf("Unexpected candidates for r1c2: %s", candidates)
+	}
+
+	if s.Candidates(0, 0) != 0 {
+		t.Error("A cell with a number has candidates")
+	}
+
+	if !s.EliminateCandidate(0, 1, 4) || s.Candidates(0, 1).Has(4) {
+		t.Error("Unable to eliminate a candidate")
+	}
+
+	// The boxes of the 6x6 board aren't square.
+	board := &sudoku.Sudoku{N: 6, Seed: 3}
+	board.Init()
+	board.Fill()
+
+	for _, b := range []*sudoku.Sudoku{s, board.GeneratePuzzle()} {
+		n := int(b.N)
+		grid := b.CandidateGrid()
+
+		if len(grid) != n*n {
+			t.Fatalf("Expected %d cells in the candidate grid, got %d", n*n, len(grid))
+		}
+
+		for idx, set := range grid {
+			if set != b.Candidates(idx/n, idx%n) {
+				t.Errorf("The candidate grid doesn't match the candidates of r%dc%d", idx/n+1, idx%n+1)
+			}
+		}
+	}
+
+	if !s.RestoreCandidate(0, 1, 4) || !s.Candidates(0, 1).Has(4) {
+		t.Error("Unable to restore a candidate")
+	}
+
+	if s.EliminateCandidate(9, 0, 1) || s.EliminateCandidate(0, 1, 10) {
+		t.Error("Eliminated a candidate outside of the board")
+	}
+}
+
+func TestPencilMarks(t *testing.T) {
+	s := initSudoku()
+
+	if !s.TogglePencilMark(0, 1, 3) || !s.TogglePencilMark(0, 1, 8) || s.PencilMarks(0, 1) != sudoku.NewCandidateSet(3, 8) {
+		t.Fatal("Unable to note numbers")
+	}
+
+	s.TogglePencilMark(0, 1, 3)
+
+	if marks := s.PencilMarks(0, 1); marks.Numbers()[0] != 8 || marks.Count() != 1 {
+		t.Errorf("Unexpected pencil marks %s", marks)
+	}
+
+	if s.SetPencilMarks(0, 1, sudoku.NewCandidateSet(12)) {
+		t.Error("Noted a number bigger than the board allows")
+	}
+
+	// The notes are part of the board, so they are copied and saved along with it.
+	other := &sudoku.Sudoku{}
+	other.Copy(s)
+	data, err := json.Marshal(other)
+
+	if err != nil || other.PencilMarks(0, 1) != s.PencilMarks(0, 1) {
+		t.Fatal("The pencil marks weren't copied")
+	}
+
+	if !strings.Contains(string(data), `"notes":[[],[8],[]`) {
+		t.Errorf("The pencil marks weren't saved as numbers: %s", data)
+	}
+
+	var saved struct {
+		Notes []sudoku.CandidateSet `json:"notes"`
+	}
+
+	if err := json.Unmarshal(data, &saved); err != nil || saved.Notes[1] != sudoku.NewCandidateSet(8) {
+		t.Errorf("The pencil marks weren't saved: %v", err)
+	}
+
+	s.FillPencilMarks()
+
+	if s.PencilMarks(0, 1) != s.Candidates(0, 1) {
+		t.Error("The pencil marks weren't filled with the candidates")
+	}
+
+	s.ClearPencilMarks()
+
+	if s.PencilMarks(0, 1) != 0 {
+		t.Error("The pencil marks weren't cleared")
+	}
+}
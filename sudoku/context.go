@@ -0,0 +1,113 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNodeBudgetExhausted is the cause of an `AbortError` when the search visited all the nodes
+// of its budget (see `Budget`).
+var ErrNodeBudgetExhausted = errors.New("the node budget was exhausted")
+
+// AbortError is returned by the variants that take a context (e.g. `SolveContext`) when they
+// stop before they're done, because the context was canceled, its deadline passed or the node
+// budget ran out. `Err` is the cause, so `errors.Is` works with `context.Canceled`,
+// `context.DeadlineExceeded` and `ErrNodeBudgetExhausted`.
+type AbortError struct {
+	Nodes int64 // The number of nodes the search visited before it stopped.
+	Err   error
+}
+
+func (e *AbortError) Error() string {
+	return fmt.Sprintf("the search was stopped after %d nodes: %v", e.Nodes, e.Err)
+}
+
+func (e *AbortError) Unwrap() error {
+	return e.Err
+}
+
+// Budget holds the limits of the variants that take a context, on top of the ones of the
+// context itself (see `WithBudget`).
+type Budget struct {
+	MaxNodes int64         // The number of nodes a single call may visit; 0 for no limit.
+	Timeout  time.Duration // The deadline of the context, shared by all its calls; 0 for no limit.
+}
+
+// budgetKey is the key of the budget of a context.
+type budgetKey struct{}
+
+// WithBudget returns a copy of a context which carries a budget. Every call of a variant that
+// takes the context gets its own nodes, while the timeout is the deadline of the context, which
+// is shared by all of them.
+func WithBudget(parent context.Context, budget Budget) (context.Context, context.CancelFunc) {
+	ctx := context.WithValue(parent, budgetKey{}, budget)
+
+	if budget.Timeout > 0 {
+		return context.WithTimeout(ctx, budget.Timeout)
+	}
+
+	return context.WithCancel(ctx)
+}
+
+// checkInterval is the number of nodes between two checks of the context, since checking it
+// on every node would slow the search down.
+const checkInterval = 1024
+
+// limiter stops the searches of a single call once its context is done or it has visited all
+// the nodes of its budget. A nil limiter never stops them.
+type limiter struct {
+	ctx      context.Context
+	maxNodes int64
+	nodes    int64
+	err      error
+}
+
+// newLimiter creates the limiter of a call with a context.
+func newLimiter(ctx context.Context) *limiter {
+	l := &limiter{ctx: ctx}
+
+	if budget, ok := ctx.Value(budgetKey{}).(Budget); ok {
+		l.maxNodes = budget.MaxNodes
+	}
+
+	l.check()
+
+	return l
+}
+
+// visit counts a node of the search, and checks the context every `checkInterval` nodes.
+func (l *limiter) visit() {
+	if l == nil || l.err != nil {
+		return
+	}
+
+	l.nodes++
+
+	if l.maxNodes > 0 && l.nodes > l.maxNodes {
+		l.err = &AbortError{Nodes: l.nodes, Err: ErrNodeBudgetExhausted}
+	} else if l.nodes%checkInterval == 0 {
+		l.check()
+	}
+}
+
+// check checks the context right away, and returns the error which stopped the call, if any.
+func (l *limiter) check() error {
+	if l == nil {
+		return nil
+	}
+
+	if l.err == nil {
+		if err := l.ctx.Err(); err != nil {
+			l.err = &AbortError{Nodes: l.nodes, Err: err}
+		}
+	}
+
+	return l.err
+}
+
+// stopped returns whether the call has to stop.
+func (l *limiter) stopped() bool {
+	return l != nil && l.err != nil
+}
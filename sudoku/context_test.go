@@ -0,0 +1,158 @@
+package sudoku_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+func TestContextVariants(t *testing.T) {
+	// Without any limits, the variants do exactly what the plain functions do.
+	board := &sudoku.Sudoku{Seed: 42}
+	board.Init()
+	board.Fill()
+	puzzle := board.GeneratePuzzle()
+
+	other := &sudoku.Sudoku{Seed: 42}
+	other.Init()
+
+	if err := other.FillContext(context.Background()); err != nil || !other.IsEqual(board) {
+		t.Fatalf("Expected the same board, got %v", err)
+	}
+
+	otherPuzzle, err := other.GeneratePuzzleContext(context.Background())
+
+	if err != nil || !otherPuzzle.IsEqual(puzzle) {
+		t.Fatalf("Expected the same puzzle, got %v", err)
+	}
+
+	solved, err := otherPuzzle.SolveContext(context.Background())
+
+	if err != nil || !solved || !otherPuzzle.IsEqual(board) {
+		t.Errorf("Expected the puzzle to be solved, got %v", err)
+	}
+}
+
+func TestContextAbort(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	puzzle, _ := sudoku.ParseRowMajor(hardPuzzle)
+	empty := puzzle.CountEmpty()
+	solved, err := puzzle.SolveContext(canceled)
+
+	if solved || !errors.Is(err, context.Canceled) || puzzle.CountEmpty() != empty {
+		t.Errorf("Expected the canceled solve to leave the board alone, got %v", err)
+	}
+
+	var abort *sudoku.AbortError
+
+	if !errors.As(err, &abort) {
+		t.Errorf("Expected an AbortError, got %T", err)
+	}
+
+	// The empty board has far too many solutions to count.
+	for _, solver := range []sudoku.Solver{sudoku.BacktrackingSolver, sudoku.DLXSolver} {
+		board := &sudoku.Sudoku{Solver: solver}
+		board.Init()
+
+		ctx, cancel := sudoku.WithBudget(context.Background(), sudoku.Budget{MaxNodes: 10000})
+		count, err := board.CountSolutionsContext(ctx)
+		cancel()
+
+		if !errors.As(err, &abort) || !errors.Is(err, sudoku.ErrNodeBudgetExhausted) || abort.Nodes != 10001 || count == 0 {
+			t.Errorf("Expected the %s solver to run out of nodes, got %d solutions and %v", solver, count, err)
+		}
+
+		ctx, cancel = sudoku.WithBudget(context.Background(), sudoku.Budget{Timeout: 20 * time.Millisecond})
+		start := time.Now()
+		_, err = board.CountSolutionsContext(ctx)
+		cancel()
+
+		if !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > time.Second {
+			t.Errorf("Expected the %s solver to stop at the deadline, got %v after %s", solver, err, time.Since(start))
+		}
+	}
+
+	board := &sudoku.Sudoku{N: 16, Seed: 3}
+	board.Init()
+
+	ctx, cancel := sudoku.WithBudget(context.Background(), sudoku.Budget{MaxNodes: 50})
+	defer cancel()
+
+	if err := board.FillContext(ctx); !errors.Is(err, sudoku.ErrNodeBudgetExhausted) || board.CountEmpty() != 256 {
+		t.Errorf("Expected the fill to stop with an empty board, got %v", err)
+	}
+
+	if _, err := board.GenerateWithOptionsContext(canceled, sudoku.GenerateOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the generator to stop, got %v", err)
+	}
+}
+
+func TestHardenContext(t *testing.T) {
+	board := &sudoku.Sudoku{Seed: 7}
+	board.Init()
+	board.Fill()
+
+	// A small budget stops the hardening half way, but the puzzle keeps a single solution.
+	ctx, cancel := sudoku.WithBudget(context.Background(), sudoku.Budget{MaxNodes: 500})
+	defer cancel()
+
+	puzzle := &sudoku.Sudoku{}
+	puzzle.Copy(board)
+
+	if err := puzzle.HardenContext(ctx); !errors.Is(err, sudoku.ErrNodeBudgetExhausted) {
+		t.Fatalf("Expected the hardening to run out of nodes, got %v", err)
+	}
+
+	if puzzle.CountEmpty() == 0 || puzzle.CountSolutions() != 1 {
+		t.Errorf("Expected a puzzle with a single solution, got %d empty cells", puzzle.CountEmpty())
+	}
+}
+
+func TestGenerateMinimalContext(t *testing.T) {
+	// Generating this 16x16 puzzle takes a fraction of a second, but minimizing it takes many
+	// seconds, so the deadline passes while it's being minimized.
+	board := &sudoku.Sudoku{N: 16, Seed: 5}
+	board.Init()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := board.GenerateWithOptionsContext(ctx, sudoku.GenerateOptions{MaxDifficulty: sudoku.Extreme, Minimal: true})
+
+	var abort *sudoku.AbortError
+
+	if !errors.As(err, &abort) || !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > 2*time.Second {
+		t.Errorf("Expected the generator to stop at the deadline, got %v after %s", err, time.Since(start))
+	}
+
+	board.Init()
+	board.Fill()
+	puzzle := board.GeneratePuzzle()
+	clues := puzzle.String()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start = time.Now()
+
+	if err := puzzle.MinimizeContext(ctx); !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Expected the minimization to stop at the deadline, got %v after %s", err, time.Since(start))
+	}
+
+	if puzzle.String() != clues {
+		t.Error("Expected the minimization to leave the puzzle alone")
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := puzzle.GradeContext(canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the grading to stop, got %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package sudoku
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"strings"
+	"time"
+)
+
+// DateFormat is the format of the dates of the daily puzzles.
+const DateFormat = "2006-01-02"
+
+// dailyMaxAttempts is the number of puzzles that are tried for a daily puzzle. It's higher than
+// the default, since a missing daily puzzle can't be made up by trying another seed.
+const dailyMaxAttempts = 1000
+
+// DailyDifficulties are the difficulties of the daily puzzles, from the easiest to the hardest.
+var DailyDifficulties = []Difficulty{Easy, Medium, Hard}
+
+// ParseDailyDifficulties parses a list of difficulties, separated by commas and optionally
+// spaces. An empty string gives the difficulties of the daily puzzles.
+func ParseDailyDifficulties(names string) ([]Difficulty, error) {
+	if names == "" {
+		return DailyDifficulties, nil
+	}
+
+	difficulties := make([]Difficulty, 0)
+
+	for _, name := range strings.Split(names, ",") {
+		difficulty, err := ParseDifficulty(strings.TrimSpace(name))
+
+		if err != nil {
+			return nil, err
+		}
+
+		difficulties = append(difficulties, difficulty)
+	}
+
+	return difficulties, nil
+}
+
+// DailySeed returns the seed of the daily puzzle of a date and a difficulty. It's derived from
+// the day of the date, as it's written in its own location, and from a secret salt, so that the
+// puzzles of the coming days can't be worked out without it. The seeds fit in 53 bits, so that
+// they're read exactly by the JSON readers that use floats.
+func DailySeed(date time.Time, salt string, difficulty Difficulty) int64 {
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(date.Format(DateFormat) + "/" + difficulty.String()))
+
+	return int64(binary.BigEndian.Uint64(mac.Sum(nil)) >> 11)
+}
+
+// DailyPuzzle generates the 9x9 daily puzzle of a date and a difficulty (see `DailySeed`). The
+// same date, salt and difficulty always lead to the same puzzle, on any machine and with any
+// build of the same version. It returns the puzzle along with its solution.
+func DailyPuzzle(date time.Time, salt string, difficulty Difficulty) (*Sudoku, *Sudoku, error) {
+	return dailyPuzzle(date, salt, difficulty, nil)
+}
+
+// DailyPuzzleContext is `DailyPuzzle`, but it stops with an `AbortError` once the context is
+// done or its budget runs out (see `WithBudget`).
+func DailyPuzzleContext(ctx context.Context, date time.Time, salt string, difficulty Difficulty) (*Sudoku, *Sudoku, error) {
+	return dailyPuzzle(date, salt, difficulty, newLimiter(ctx))
+}
+
+// dailyPuzzle generates the daily puzzle of a date and a difficulty, until the limiter stops it.
+func dailyPuzzle(date time.Time, salt string, difficulty Difficulty, l *limiter) (*Sudoku, *Sudoku, error) {
+	board := &Sudoku{Seed: DailySeed(date, salt, difficulty)}
+	board.Init()
+
+	puzzle, err := board.generateWithOptions(GenerateOptions{
+		MinDifficulty: difficulty,
+		MaxDifficulty: difficulty,
+		MaxAttempts:   dailyMaxAttempts,
+	}, l)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return puzzle, board, nil
+}
@@ -0,0 +1,114 @@
+package sudoku_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+func TestDailySeed(t *testing.T) {
+	date := time.Date(2024, 3, 1, 23, 30, 0, 0, time.UTC)
+	seed := sudoku.DailySeed(date, "salt", sudoku.Easy)
+
+	if seed != sudoku.DailySeed(time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC), "salt", sudoku.Easy) {
+		t.Error("The seed changed within the same day")
+	}
+
+	if seed < 0 || seed >= 1<<53 {
+		t.Errorf("The seed %d doesn't fit in 53 bits", seed)
+	}
+
+	others := []int64{
+		sudoku.DailySeed(date, "pepper", sudoku.Easy),
+		sudoku.DailySeed(date.AddDate(0, 0, 1), "salt", sudoku.Easy),
+		sudoku.DailySeed(date, "salt", sudoku.Hard),
+	}
+
+	for i, other := range others {
+		if other == seed {
+			t.Errorf("Expected the seed %d to differ from %d", i, seed)
+		}
+	}
+}
+
+func TestDailyPuzzle(t *testing.T) {
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, difficulty := range []sudoku.Difficulty{sudoku.Easy, sudoku.Medium} {
+		puzzle, solution, err := sudoku.DailyPuzzle(date, "salt", difficulty)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rating, err := puzzle.Grade()
+
+		if err != nil || rating.Difficulty != difficulty || puzzle.CountSolutions() != 1 || solution.CountEmpty() != 0 {
+			t.Errorf("Expected a unique %s puzzle, got a %s one (%v)", difficulty, rating.Difficulty, err)
+		}
+
+		again, _, _ := sudoku.DailyPuzzle(date, "salt", difficulty)
+
+		if again.String() != puzzle.String() {
+			t.Errorf("The %s puzzle of the day changed", difficulty)
+		}
+	}
+}
+
+// The daily puzzles are published, so every build has to give the same ones. A change to the
+// seeds or to the way the generator uses them fails this test, which is a breaking change.
+func TestDailyPuzzleGolden(t *testing.T) {
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	golden := []struct {
+		difficulty sudoku.Difficulty
+		seed       int64
+		puzzle     string
+	}{
+		{sudoku.Easy, 471390171296765, "........519..26..75...47....934...56..2.5.7..17...649....53...76..98..318........"},
+		{sudoku.Medium, 2278372539545912, ".81...4...3...8...2...6....3.4..5.62.92.4.85.87.6..1.3....4...3...9...6...9...72."},
+	}
+
+	for _, g := range golden {
+		if seed := sudoku.DailySeed(date, "salt", g.difficulty); seed != g.seed {
+			t.Errorf("Expected the seed of the %s puzzle to be %d, got %d", g.difficulty, g.seed, seed)
+		}
+
+		puzzle, _, err := sudoku.DailyPuzzle(date, "salt", g.difficulty)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if puzzle.String() != g.puzzle {
+			t.Errorf("Expected the %s puzzle to be %s, got %s", g.difficulty, g.puzzle, puzzle.String())
+		}
+	}
+}
+
+func TestParseDailyDifficulties(t *testing.T) {
+	difficulties, err := sudoku.ParseDailyDifficulties("easy, Hard")
+
+	if err != nil || len(difficulties) != 2 || difficulties[0] != sudoku.Easy || difficulties[1] != sudoku.Hard {
+		t.Errorf("Unexpected difficulties %v (%v)", difficulties, err)
+	}
+
+	if difficulties, _ := sudoku.ParseDailyDifficulties(""); len(difficulties) != len(sudoku.DailyDifficulties) {
+		t.Errorf("Expected the daily difficulties, got %v", difficulties)
+	}
+
+	if _, err := sudoku.ParseDailyDifficulties("easy,impossible"); err == nil {
+		t.Error("An unknown difficulty was parsed")
+	}
+}
+
+func TestDailyPuzzleContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, _, err := sudoku.DailyPuzzleContext(ctx, time.Now(), "salt", sudoku.Hard); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the daily puzzle to be canceled, got %v", err)
+	}
+}
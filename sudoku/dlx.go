@@ -0,0 +1,341 @@
+package sudoku
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Solver is the algorithm which `Solve`, `CountSolutions` and `HasMultipleSolutions` use.
+type Solver int
+
+const (
+	BacktrackingSolver Solver = iota // Backtracking over the cells, with the numbers kept as bits (the default).
+	DLXSolver                        // Knuth's Algorithm X with Dancing Links, as an exact cover problem.
+)
+
+var solverNames = []string{"backtracking", "dlx"}
+
+// String returns the name of the solver.
+func (solver Solver) String() string {
+	if solver < BacktrackingSolver || solver > DLXSolver {
+		return fmt.Sprintf("Solver(%d)", int(solver))
+	}
+
+	return solverNames[solver]
+}
+
+// ParseSolver returns the solver with a specific name, regardless of its case.
+func ParseSolver(name string) (Solver, error) {
+	for i, solverName := range solverNames {
+		if strings.EqualFold(name, solverName) {
+			return Solver(i), nil
+		}
+	}
+
+	return BacktrackingSolver, fmt.Errorf("unknown solver \"%s\"", name)
+}
+
+// Constraint is a group of cells, on top of the rows, columns and boxes, in which a number can't
+// appear twice. If the group has as many cells as the board has rows, every number appears in
+// it exactly once. Constraints describe variants of the puzzle, like the diagonals of an
+// X-Sudoku, and only the DLX solver takes them into account.
+type Constraint []Cell
+
+// DiagonalConstraints returns the constraints of an X-Sudoku of size `n`, where the numbers of
+// both diagonals are unique too.
+func DiagonalConstraints(n int) []Constraint {
+	main := make(Constraint, n)
+	anti := make(Constraint, n)
+
+	for i := 0; i < n; i++ {
+		main[i] = Cell{Row: i, Col: i}
+		anti[i] = Cell{Row: i, Col: n - 1 - i}
+	}
+
+	return []Constraint{main, anti}
+}
+
+// ExactCover is a generic exact cover problem which is solved with Knuth's Algorithm X, using
+// Dancing Links. A solution is a set of rows which covers each primary column exactly once and
+// each secondary column at most once.
+type ExactCover struct {
+	// The nodes are kept in flat slices, where the first nodes are the headers of the columns,
+	// preceded by the root (0). Each node links to its neighbours by their index.
+	left, right, up, down, column []int
+	size                          []int // The number of rows in each column, by its header.
+	rowOf                         []int // The row of each node.
+	rows                          int
+
+	// The search gives up once it has visited more than `maxNodes` (if set).
+	nodes    int64
+	maxNodes int64
+
+	// limit stops the search of a call with a context (see `limiter`).
+	limit *limiter
+}
+
+// NewExactCover creates an exact cover problem with `primary` columns, which have to be covered,
+// followed by `secondary` ones, which may be left uncovered.
+func NewExactCover(primary, secondary int) *ExactCover {
+	columns := primary + secondary
+	x := &ExactCover{
+		left:   make([]int, columns+1),
+		right:  make([]int, columns+1),
+		up:     make([]int, columns+1),
+		down:   make([]int, columns+1),
+		column: make([]int, columns+1),
+		size:   make([]int, columns+1),
+		rowOf:  make([]int, columns+1),
+	}
+
+	for i := 0; i <= columns; i++ {
+		x.up[i], x.down[i], x.column[i], x.rowOf[i] = i, i, i, -1
+		x.left[i], x.right[i] = i, i
+	}
+
+	// Only the primary columns are linked to the root, so the search never has to cover the
+	// secondary ones.
+	for i := 1; i <= primary; i++ {
+		x.left[i], x.right[i] = i-1, (i+1)%(primary+1)
+	}
+
+	x.left[0], x.right[0] = primary, 1%(primary+1)
+
+	return x
+}
+
+// AddRow adds a row which covers the given columns (starting from 0) and returns its index.
+func (x *ExactCover) AddRow(columns ...int) int {
+	row := x.rows
+	first := -1
+	x.rows++
+
+	for _, col := range columns {
+		header := col + 1
+		node := len(x.column)
+
+		x.column = append(x.column, header)
+		x.rowOf = append(x.rowOf, row)
+		x.up = append(x.up, x.up[header])
+		x.down = append(x.down, header)
+		x.down[x.up[header]] = node
+		x.up[header] = node
+		x.size[header]++
+
+		if first < 0 {
+			first = node
+			x.left = append(x.left, node)
+			x.right = append(x.right, node)
+		} else {
+			x.left = append(x.left, x.left[first])
+			x.right = append(x.right, first)
+			x.right[x.left[first]] = node
+			x.left[first] = node
+		}
+	}
+
+	return row
+}
+
+// Search goes through the solutions and calls `found` with the rows of each one of them. The
+// search stops, returning true, as soon as `found` returns true.
+func (x *ExactCover) Search(found func(rows []int) bool) bool {
+	x.nodes = 0
+
+	return x.search(make([]int, 0), found)
+}
+
+// Count returns the number of solutions. It stops once it has found `limit` solutions, unless
+// `limit` is 0.
+func (x *ExactCover) Count(limit int64) int64 {
+	var count int64
+
+	x.Search(func(rows []int) bool {
+		count++
+
+		return limit > 0 && count >= limit
+	})
+
+	return count
+}
+
+// exhausted returns whether the search has run out of nodes, or has to stop for its limiter.
+func (x *ExactCover) exhausted() bool {
+	return (x.maxNodes > 0 && x.nodes > x.maxNodes) || x.limit.stopped()
+}
+
+func (x *ExactCover) search(solution []int, found func(rows []int) bool) bool {
+	x.nodes++
+	x.limit.visit()
+
+	if x.exhausted() {
+		return true
+	}
+
+	if x.right[0] == 0 {
+		return found(solution)
+	}
+
+	// Always go for the column with the fewest rows, since it leads to the fewest branches.
+	best := x.right[0]
+
+	for col := x.right[best]; col != 0; col = x.right[col] {
+		if x.size[col] < x.size[best] {
+			best = col
+		}
+	}
+
+	if x.size[best] == 0 {
+		return false
+	}
+
+	x.cover(best)
+	defer x.uncover(best)
+
+	for node := x.down[best]; node != best; node = x.down[node] {
+		for other := x.right[node]; other != node; other = x.right[other] {
+			x.cover(x.column[other])
+		}
+
+		stop := x.search(append(solution, x.rowOf[node]), found)
+
+		for other := x.left[node]; other != node; other = x.left[other] {
+			x.uncover(x.column[other])
+		}
+
+		if stop {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cover removes a column from the header list, along with all the rows that cover it.
+func (x *ExactCover) cover(col int) {
+	x.right[x.left[col]] = x.right[col]
+	x.left[x.right[col]] = x.left[col]
+
+	for node := x.down[col]; node != col; node = x.down[node] {
+		for other := x.right[node]; other != node; other = x.right[other] {
+			x.down[x.up[other]] = x.down[other]
+			x.up[x.down[other]] = x.up[other]
+			x.size[x.column[other]]--
+		}
+	}
+}
+
+// uncover puts back a column which was removed by `cover`, in the reverse order.
+func (x *ExactCover) uncover(col int) {
+	for node := x.up[col]; node != col; node = x.up[node] {
+		for other := x.left[node]; other != node; other = x.left[other] {
+			x.size[x.column[other]]++
+			x.down[x.up[other]] = other
+			x.up[x.down[other]] = other
+		}
+	}
+
+	x.right[x.left[col]] = col
+	x.left[x.right[col]] = col
+}
+
+// sudokuCover turns a board into an exact cover problem. Every number which can go in a cell is
+// a row, which covers the cell, the number in the row, column and box of the cell, and the
+// number in each of the constraints of the cell. It also returns the cell and the number of
+// each row.
+func sudokuCover(s *Sudoku) (*ExactCover, []Candidate) {
+	n := int(s.N)
+	constraintsOf := make([][]int, n*n)
+	primary := n * n * 4
+	secondary := 0
+
+	// The constraints which cover a whole unit are primary columns, while the rest are
+	// secondary ones and are numbered after all the primary ones.
+	columnOf := make([]int, len(s.Constraints))
+
+	for i, constraint := range s.Constraints {
+		if len(constraint) == n {
+			columnOf[i] = primary
+			primary += n
+		}
+	}
+
+	for i, constraint := range s.Constraints {
+		if len(constraint) != n {
+			columnOf[i] = secondary
+			secondary += n
+		}
+
+		for _, c := range constraint {
+			if c.Row >= 0 && c.Row < n && c.Col >= 0 && c.Col < n {
+				constraintsOf[c.Row*n+c.Col] = append(constraintsOf[c.Row*n+c.Col], i)
+			}
+		}
+	}
+
+	x := NewExactCover(primary, secondary)
+	candidates := make([]Candidate, 0, n*n*n)
+	columns := make([]int, 0, 4+len(s.Constraints))
+
+	for row := 0; row < n; row++ {
+		for col := 0; col < n; col++ {
+			given := s.GetCell(row, col)
+			boxIdx, _ := s.boxPosFromRowCol(row, col)
+
+			for num := 1; num <= n; num++ {
+				if given != 0 && int(given) != num {
+					continue
+				}
+
+				columns = append(columns[:0],
+					row*n+col,
+					n*n+row*n+num-1,
+					n*n*2+col*n+num-1,
+					n*n*3+boxIdx*n+num-1,
+				)
+
+				for _, i := range constraintsOf[row*n+col] {
+					if len(s.Constraints[i]) == n {
+						columns = append(columns, columnOf[i]+num-1)
+					} else {
+						columns = append(columns, primary+columnOf[i]+num-1)
+					}
+				}
+
+				x.AddRow(columns...)
+				candidates = append(candidates, Candidate{Cell: Cell{Row: row, Col: col}, Value: uint8(num)})
+			}
+		}
+	}
+
+	return x, candidates
+}
+
+// usesDLX returns whether the board is solved with the DLX solver, which is the case if it was
+// picked or if the board has constraints that only it supports.
+func (s *Sudoku) usesDLX() bool {
+	return s.Solver == DLXSolver || len(s.Constraints) > 0
+}
+
+// solveDLX solves the board with the DLX solver and writes the first solution to it.
+func (s *Sudoku) solveDLX(l *limiter) bool {
+	x, candidates := sudokuCover(s)
+	x.limit = l
+
+	return x.Search(func(rows []int) bool {
+		writeCover(s, rows, candidates)
+
+		return true
+	})
+}
+
+// writeCover writes a solution of the exact cover problem of a board (see `sudokuCover`) to it.
+func writeCover(s *Sudoku, rows []int, candidates []Candidate) {
+	for _, row := range rows {
+		s.SetCell(candidates[row].Row, candidates[row].Col, 0)
+	}
+
+	for _, row := range rows {
+		s.SetCell(candidates[row].Row, candidates[row].Col, candidates[row].Value)
+	}
+}
@@ -0,0 +1,115 @@
+package sudoku_test
+
+import (
+	"testing"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+func TestExactCover(t *testing.T) {
+	// The example from Knuth's paper, which has a single solution.
+	x := sudoku.NewExactCover(7, 0)
+	x.AddRow(2, 4, 5)
+	x.AddRow(0, 3, 6)
+	x.AddRow(1, 2, 5)
+	x.AddRow(0, 3)
+	x.AddRow(1, 6)
+	x.AddRow(3, 4, 6)
+
+	var solution []int
+
+	x.Search(func(rows []int) bool {
+		solution = append([]int{}, rows...)
+
+		return true
+	})
+
+	if len(solution) != 3 || x.Count(0) != 1 {
+		t.Fatalf("Unexpected solution %v", solution)
+	}
+
+	for _, row := range solution {
+		if row != 0 && row != 3 && row != 4 {
+			t.Errorf("Row %d isn't part of the solution", row)
+		}
+	}
+}
+
+func TestDLXSolver(t *testing.T) {
+	s := initSudoku()
+	s.Solver = sudoku.DLXSolver
+	expected := initSudoku()
+
+	if s.CountSolutions() != expected.CountSolutions() {
+		t.Error("The solvers found a different number of solutions")
+	}
+
+	if s.HasMultipleSolutions() || !s.Solve() {
+		t.Fatal("Unable to solve sudoku with DLX")
+	}
+
+	expected.Solve()
+
+	if !s.IsEqual(expected) {
+		t.Error("The solvers found different solutions")
+	}
+
+	// A board with a few more empty cells has more than one solution.
+	board := &sudoku.Sudoku{N: 6, Seed: 3, Solver: sudoku.DLXSolver}
+	board.Init()
+	board.Fill()
+
+	for col := 0; col < 6; col++ {
+		board.SetCell(0, col, 0)
+		board.SetCell(1, col, 0)
+	}
+
+	backtracking := &sudoku.Sudoku{}
+	backtracking.Copy(board)
+	backtracking.Solver = sudoku.BacktrackingSolver
+
+	if count := board.CountSolutions(); count < 2 || count != backtracking.CountSolutions() {
+		t.Errorf("The solvers found a different number of solutions (%d)", count)
+	}
+
+	if !board.HasMultipleSolutions() {
+		t.Error("Expected multiple solutions")
+	}
+}
+
+func TestDLXConstraints(t *testing.T) {
+	s := &sudoku.Sudoku{Constraints: sudoku.DiagonalConstraints(9)}
+	s.Init()
+
+	if !s.Solve() {
+		t.Fatal("Unable to solve an empty X-Sudoku")
+	}
+
+	for _, constraint := range s.Constraints {
+		seen := make(map[uint8]bool)
+
+		for _, c := range constraint {
+			seen[s.GetCell(c.Row, c.Col)] = true
+		}
+
+		if len(seen) != 9 {
+			t.Errorf("A diagonal has repeated numbers: %v", constraint)
+		}
+	}
+
+	// A constraint smaller than a unit only keeps its numbers apart.
+	small := &sudoku.Sudoku{N: 4}
+	small.Init()
+	total := small.CountSolutions()
+	small.Constraints = []sudoku.Constraint{{{Row: 0, Col: 0}, {Row: 3, Col: 3}}}
+
+	if count := small.CountSolutions(); count == 0 || count >= total {
+		t.Errorf("Expected fewer than %d solutions, got %d", total, count)
+	}
+
+	small.Solve()
+
+	if small.GetCell(0, 0) == small.GetCell(3, 3) {
+		t.Error("The cells of the constraint have the same number")
+	}
+}
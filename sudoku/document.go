@@ -0,0 +1,180 @@
+package sudoku
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+)
+
+// Version is the version of the generator, which is recorded in the documents it saves.
+const Version = "1.0.0"
+
+// DocumentVersion is the version of the format of `Document`. It changes whenever a field is
+// renamed, removed or changes its meaning, so that older files can still be told apart.
+const DocumentVersion = 1
+
+// DocumentSchema is the JSON Schema of `Document`, which other programs can use to validate the
+// files before reading them.
+//
+//go:embed document.schema.json
+var DocumentSchema string
+
+// Document is a generated puzzle along with its solution and the details of how it was made,
+// which is what gets saved to JSON files. Unlike a `Sudoku`, the boards are written as strings,
+// row by row (see `RowMajorString`), so that other software can read them as they are.
+type Document struct {
+	Version   int    `json:"version"`
+	Generator string `json:"generator"` // The version of the generator that made the puzzle.
+
+	N         uint8 `json:"n"`
+	BoxWidth  uint8 `json:"box_width"`
+	BoxHeight uint8 `json:"box_height"`
+	Seed      int64 `json:"seed"`
+
+	Symmetry     Symmetry `json:"symmetry"`
+	SymmetryMask string   `json:"symmetry_mask,omitempty"`
+
+	Clues    int     `json:"clues"`
+	Puzzle   string  `json:"puzzle"`
+	Solution string  `json:"solution"`
+	Rating   *Rating `json:"rating,omitempty"`
+
+	// GenerationTime is how long it took to generate the puzzle, in milliseconds. It's only set
+	// by the caller, since it's measured along with the solution.
+	GenerationTime float64 `json:"generation_ms,omitempty"`
+}
+
+// NewDocument returns the document of a puzzle and its solution. The details of the puzzle,
+// like its seed and its symmetry, are taken from the puzzle itself.
+func NewDocument(puzzle, solution *Sudoku) *Document {
+	return &Document{
+		Version:      DocumentVersion,
+		Generator:    Version,
+		N:            puzzle.N,
+		BoxWidth:     puzzle.BoxWidth,
+		BoxHeight:    puzzle.BoxHeight,
+		Seed:         puzzle.Seed,
+		Symmetry:     puzzle.Symmetry,
+		SymmetryMask: puzzle.SymmetryMask,
+		Clues:        int(puzzle.N)*int(puzzle.N) - puzzle.CountEmpty(),
+		Puzzle:       puzzle.RowMajorString(),
+		Solution:     solution.RowMajorString(),
+		Rating:       puzzle.Rating,
+	}
+}
+
+// LoadDocument reads a document from a JSON file and validates it (see `Validate`).
+func LoadDocument(fileName string) (*Document, error) {
+	documentJson, err := os.ReadFile(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{}
+
+	if err = json.Unmarshal(documentJson, doc); err != nil {
+		return nil, fmt.Errorf("unable to load %s: %w", fileName, err)
+	}
+
+	if err = doc.Validate(); err != nil {
+		return nil, fmt.Errorf("unable to load %s: %w", fileName, err)
+	}
+
+	return doc, nil
+}
+
+// Save creates a JSON file for this document. A "@seed" file name is replaced with one that has
+// the seed of the puzzle, like `Sudoku.Save` does.
+func (d *Document) Save(fileName string) error {
+	documentJson, err := json.Marshal(d)
+
+	if err != nil {
+		return err
+	}
+
+	if fileName == "@seed" {
+		fileName = fmt.Sprintf("sudoku-%d.json", d.Seed)
+	}
+
+	return os.WriteFile(fileName, documentJson, 0644)
+}
+
+// Validate returns an error if the document has a version this package can't read, or if its
+// boards don't fit its size, or if the solution doesn't match the clues of the puzzle.
+func (d *Document) Validate() error {
+	_, _, err := d.Boards()
+
+	return err
+}
+
+// Boards returns the puzzle and the solution of the document, as boards that are ready to be
+// used. Both of them have the seed, the symmetry and the rating of the document.
+func (d *Document) Boards() (*Sudoku, *Sudoku, error) {
+	if d.Version < 1 || d.Version > DocumentVersion {
+		return nil, nil, fmt.Errorf("unsupported document version %d", d.Version)
+	}
+
+	puzzle, err := ParseRowMajorWithBoxSize(d.Puzzle, int(d.BoxWidth), int(d.BoxHeight))
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid puzzle: %w", err)
+	}
+
+	solution, err := ParseRowMajorWithBoxSize(d.Solution, int(d.BoxWidth), int(d.BoxHeight))
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid solution: %w", err)
+	}
+
+	n := int(puzzle.N)
+
+	if puzzle.N != d.N || solution.N != d.N {
+		return nil, nil, fmt.Errorf("the boards don't have %d rows and columns", d.N)
+	}
+
+	if clues := n*n - puzzle.CountEmpty(); clues != d.Clues {
+		return nil, nil, fmt.Errorf("the puzzle has %d clues instead of %d", clues, d.Clues)
+	}
+
+	if solution.CountEmpty() != 0 {
+		return nil, nil, fmt.Errorf("the solution has %d empty cells", solution.CountEmpty())
+	}
+
+	// The boards are only checked box by box while they're parsed, so every row and column of the
+	// solution needs to be checked too.
+	g := newGrid(solution)
+	invalid := g.invalid
+	g.release()
+
+	if invalid {
+		return nil, nil, errors.New("the solution has the same number twice in a row, column or box")
+	}
+
+	for row := 0; row < n; row++ {
+		for col := 0; col < n; col++ {
+			if num := puzzle.GetCell(row, col); num != 0 && num != solution.GetCell(row, col) {
+				return nil, nil, fmt.Errorf("the solution doesn't match the clue of r%dc%d", row+1, col+1)
+			}
+		}
+	}
+
+	if d.Symmetry == CustomSymmetry {
+		if err := ValidateSymmetryMask(n, d.SymmetryMask); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, board := range []*Sudoku{puzzle, solution} {
+		board.Seed = d.Seed
+		board.Symmetry = d.Symmetry
+		board.SymmetryMask = d.SymmetryMask
+		board.Rating = d.Rating
+		board.rand = rand.New(rand.NewSource(d.Seed))
+	}
+
+	return puzzle, solution, nil
+}
@@ -0,0 +1,129 @@
+package sudoku_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+func TestDocument(t *testing.T) {
+	board := &sudoku.Sudoku{N: 12, Seed: 4, Symmetry: sudoku.VerticalSymmetry}
+	board.Init()
+	board.Fill()
+
+	puzzle := board.GeneratePuzzle()
+	puzzle.Rating, _ = puzzle.Grade()
+
+	doc := sudoku.NewDocument(puzzle, board)
+	doc.GenerationTime = 1.5
+
+	if doc.Version != sudoku.DocumentVersion || doc.Clues != 144-puzzle.CountEmpty() {
+		t.Errorf("Unexpected version %d or clues %d", doc.Version, doc.Clues)
+	}
+
+	fileName := filepath.Join(t.TempDir(), "puzzle.json")
+
+	if err := doc.Save(fileName); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := sudoku.LoadDocument(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded.Rating == nil || loaded.Rating.Difficulty != puzzle.Rating.Difficulty || loaded.Rating.Steps != puzzle.Rating.Steps {
+		t.Error("The rating wasn't loaded")
+	}
+
+	loaded.Rating = doc.Rating
+
+	if *loaded != *doc {
+		t.Errorf("The loaded document is not the same as the saved one: %+v", loaded)
+	}
+
+	p, solution, err := loaded.Boards()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !p.IsEqual(puzzle) || !solution.IsEqual(board) || p.Symmetry != sudoku.VerticalSymmetry || p.Seed != 4 {
+		t.Error("The boards of the document are not the same as the original ones")
+	}
+
+	// Load reads the puzzle of a document, just like it reads a board.
+	if p, err = sudoku.Load(fileName); err != nil || !p.IsEqual(puzzle) {
+		t.Errorf("Unable to load the puzzle of the document: %v", err)
+	}
+
+	invalid := map[string]func(d *sudoku.Document){
+		"version":  func(d *sudoku.Document) { d.Version = sudoku.DocumentVersion + 1 },
+		"clues":    func(d *sudoku.Document) { d.Clues++ },
+		"size":     func(d *sudoku.Document) { d.N = 9 },
+		"puzzle":   func(d *sudoku.Document) { d.Puzzle = d.Puzzle[1:] },
+		"solution": func(d *sudoku.Document) { d.Solution = d.Puzzle },
+	}
+
+	for name, change := range invalid {
+		d := *doc
+		change(&d)
+
+		if err := d.Validate(); err == nil {
+			t.Errorf("A document with an invalid %s was validated", name)
+		}
+	}
+	// Every box of this solution is fine, but its rows and columns repeat numbers.
+	repeated := sudoku.Document{
+		Version:   sudoku.DocumentVersion,
+		N:         4,
+		BoxWidth:  2,
+		BoxHeight: 2,
+		Puzzle:    "................",
+		Solution:  "1212343412123434",
+	}
+
+	if err := repeated.Validate(); err == nil {
+		t.Error("A solution with repeated numbers in its rows and columns was validated")
+	}
+}
+
+func TestDocumentSchema(t *testing.T) {
+	var schema struct {
+		Required   []string                   `json:"required"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+
+	if err := json.Unmarshal([]byte(sudoku.DocumentSchema), &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	board := &sudoku.Sudoku{Seed: 1}
+	board.Init()
+	board.Fill()
+
+	puzzle := board.GeneratePuzzle()
+	puzzle.Rating, _ = puzzle.Grade()
+
+	doc := sudoku.NewDocument(puzzle, board)
+	doc.GenerationTime = 1
+	docJson, _ := json.Marshal(doc)
+	fields := make(map[string]json.RawMessage)
+	json.Unmarshal(docJson, &fields)
+
+	// Every field of the document has to be described by the schema, and vice versa.
+	for name := range fields {
+		if _, ok := schema.Properties[name]; !ok {
+			t.Errorf("The schema doesn't describe \"%s\"", name)
+		}
+	}
+
+	for _, name := range schema.Required {
+		if _, ok := fields[name]; !ok {
+			t.Errorf("The document doesn't have \"%s\"", name)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package sudoku
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Format is the order of the cells in a puzzle string.
+type Format int
+
+const (
+	BoxMajor            Format = iota // Box by box, like `ParseBoard` and `String`.
+	RowMajor                          // Row by row, like most other Sudoku tools and collections.
+	CandidateGridFormat               // A grid with the candidates of every cell, like HoDoKu's.
+)
+
+var formatNames = []string{"box", "row", "candidates"}
+
+// String returns the name of the format.
+func (f Format) String() string {
+	if f < BoxMajor || f > CandidateGridFormat {
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+
+	return formatNames[f]
+}
+
+// ParseFormat returns the format with a specific name, regardless of its case.
+func ParseFormat(name string) (Format, error) {
+	for i, formatName := range formatNames {
+		if strings.EqualFold(name, formatName) {
+			return Format(i), nil
+		}
+	}
+
+	return BoxMajor, fmt.Errorf("unknown format \"%s\"", name)
+}
+
+// ParseBoardFormat parses a board in a specific format, with the default size of the boxes if
+// both `boxWidth` and `boxHeight` are 0.
+func ParseBoardFormat(boardStr string, format Format, boxWidth, boxHeight int) (*Sudoku, error) {
+	switch format {
+	case RowMajor:
+		return ParseRowMajorWithBoxSize(boardStr, boxWidth, boxHeight)
+	case CandidateGridFormat:
+		return ParseCandidateGridWithBoxSize(boardStr, boxWidth, boxHeight)
+	}
+
+	return ParseBoardWithBoxSize(boardStr, boxWidth, boxHeight)
+}
+
+// Format returns the board as a string in a specific format.
+func (s *Sudoku) Format(format Format) string {
+	switch format {
+	case RowMajor:
+		return s.RowMajorString()
+	case CandidateGridFormat:
+		return s.CandidateGridString()
+	}
+
+	return s.String()
+}
+
+// ParseRowMajor parses a board whose cells are listed row by row, which is how most other
+// Sudoku tools write them. Empty cells are represented with a ".", a "0" or a "_", while
+// whitespace and any other separators (like "|", "-" or "+") are ignored, so a grid which is split
+// into lines and boxes can be parsed as is. The size of the board is derived from the number of
+// cells.
+func ParseRowMajor(boardStr string) (*Sudoku, error) {
+	return ParseRowMajorWithBoxSize(boardStr, 0, 0)
+}
+
+// ParseRowMajorWithBoxSize parses a board just like `ParseRowMajor`, but for boards whose
+// boxes are `boxWidth` columns wide and `boxHeight` rows tall. The default size of the boxes is
+// used if both are 0.
+func ParseRowMajorWithBoxSize(boardStr string, boxWidth, boxHeight int) (*Sudoku, error) {
+	cells := make([]rune, 0, len(boardStr))
+
+	for _, c := range boardStr {
+		if c == '.' || c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c) {
+			cells = append(cells, c)
+		}
+	}
+
+	n := int(math.Sqrt(float64(len(cells))))
+
+	if n*n != len(cells) || ValidateSize(n) != nil {
+		return nil, fmt.Errorf("invalid number of cells %d", len(cells))
+	}
+
+	if boxWidth != 0 || boxHeight != 0 {
+		if err := ValidateBoxSize(n, boxWidth, boxHeight); err != nil {
+			return nil, err
+		}
+	}
+
+	board := &Sudoku{N: uint8(n), BoxWidth: uint8(boxWidth), BoxHeight: uint8(boxHeight)}
+	board.Init()
+
+	for i, c := range cells {
+		if c == '.' || c == '_' || c == '0' {
+			continue
+		}
+
+		num := ParseSymbol(c)
+
+		if num == 0 || num > board.N {
+			return nil, fmt.Errorf("invalid character \"%c\" at r%dc%d", c, i/n+1, i%n+1)
+		}
+
+		if !board.SetCell(i/n, i%n, num) {
+			return nil, fmt.Errorf("unable to insert \"%c\" at r%dc%d, since its box already has it", c, i/n+1, i%n+1)
+		}
+	}
+
+	return board, nil
+}
+
+// RowMajorString returns the board as a string of its cells, row by row, with a "." for each
+// empty one.
+func (s *Sudoku) RowMajorString() string {
+	var str strings.Builder
+	n := int(s.N)
+
+	for row := 0; row < n; row++ {
+		for col := 0; col < n; col++ {
+			str.WriteByte(Symbol(s.GetCell(row, col)))
+		}
+	}
+
+	return str.String()
+}
+
+// ParseCandidateGrid parses a board from a grid with the candidates of every cell, row by row,
+// like the ones HoDoKu and SudokuWiki print:
+//
+//	.-------------.-------------.
+//	| 4   2   1   | 6   8   3   | ...
+//	| 68  59  369 | 1   25  47  | ...
+//
+// A cell with a single number has it placed, even if it was a naked single that wasn't placed
+// yet, while the rest get the numbers that aren't listed as eliminated candidates (see
+// `EliminateCandidate`), so that the logical solver and the hints carry on from where the grid
+// was left. The borders can be drawn with any characters other than letters and digits. The size
+// of the board is derived from the number of cells.
+func ParseCandidateGrid(gridStr string) (*Sudoku, error) {
+	return ParseCandidateGridWithBoxSize(gridStr, 0, 0)
+}
+
+// ParseCandidateGridWithBoxSize parses a grid just like `ParseCandidateGrid`, but for boards
+// whose boxes are `boxWidth` columns wide and `boxHeight` rows tall. The default size of the boxes
+// is used if both are 0.
+func ParseCandidateGridWithBoxSize(gridStr string, boxWidth, boxHeight int) (*Sudoku, error) {
+	cells := strings.FieldsFunc(gridStr, func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+	n := int(math.Sqrt(float64(len(cells))))
+
+	if n*n != len(cells) || ValidateSize(n) != nil {
+		return nil, fmt.Errorf("invalid number of cells %d", len(cells))
+	}
+
+	if boxWidth != 0 || boxHeight != 0 {
+		if err := ValidateBoxSize(n, boxWidth, boxHeight); err != nil {
+			return nil, err
+		}
+	}
+
+	board := &Sudoku{N: uint8(n), BoxWidth: uint8(boxWidth), BoxHeight: uint8(boxHeight)}
+	board.Init()
+	listed := make([]CandidateSet, len(cells))
+
+	for i, cell := range cells {
+		for _, c := range cell {
+			num := ParseSymbol(c)
+
+			if num == 0 || num > board.N {
+				return nil, fmt.Errorf("invalid candidate \"%c\" at r%dc%d", c, i/n+1, i%n+1)
+			}
+
+			listed[i] = listed[i].Add(num)
+		}
+
+		if listed[i].Count() == 1 && !board.SetCell(i/n, i%n, listed[i].Numbers()[0]) {
+			return nil, fmt.Errorf("unable to insert \"%s\" at r%dc%d, since its box already has it", cell, i/n+1, i%n+1)
+		}
+	}
+
+	// The candidates depend on all the numbers, so they're only eliminated once they're placed.
+	candidates := board.CandidateGrid()
+
+	for i, set := range listed {
+		if set.Count() > 1 && candidates[i]&^set != 0 {
+			if board.Eliminations == nil {
+				board.Eliminations = make([]CandidateSet, len(cells))
+			}
+
+			board.Eliminations[i] = candidates[i] &^ set
+		}
+	}
+
+	return board, nil
+}
+
+// CandidateGridString returns the board as a grid with the number or the candidates of every
+// cell (see `ParseCandidateGrid`), with the columns lined up.
+func (s *Sudoku) CandidateGridString() string {
+	n, boxWidth, boxHeight := int(s.N), int(s.BoxWidth), int(s.BoxHeight)
+	candidates := s.CandidateGrid()
+	cells := make([]string, n*n)
+	widths := make([]int, n)
+
+	for i := range cells {
+		if num := s.GetCell(i/n, i%n); num != 0 {
+			cells[i] = string(Symbol(num))
+		} else {
+			var cell strings.Builder
+
+			for _, num := range candidates[i].Numbers() {
+				cell.WriteByte(Symbol(num))
+			}
+
+			cells[i] = cell.String()
+		}
+
+		if len(cells[i]) > widths[i%n] {
+			widths[i%n] = len(cells[i])
+		}
+	}
+
+	var str strings.Builder
+
+	// Each border has a corner at both ends and a joint between the boxes.
+	border := func(corner, joint byte) {
+		str.WriteByte(corner)
+
+		for col := 0; col < n; col += boxWidth {
+			if col > 0 {
+				str.WriteByte(joint)
+			}
+
+			// The cells are separated by two spaces and the boxes are padded by one on each side.
+			dashes := 2 * boxWidth
+
+			for _, width := range widths[col : col+boxWidth] {
+				dashes += width
+			}
+
+			str.WriteString(strings.Repeat("-", dashes))
+		}
+
+		str.WriteByte(corner)
+		str.WriteByte('\n')
+	}
+
+	border('.', '.')
+
+	for row := 0; row < n; row++ {
+		if row > 0 && row%boxHeight == 0 {
+			border(':', '+')
+		}
+
+		for col := 0; col < n; col++ {
+			if col%boxWidth == 0 {
+				str.WriteString("| ")
+			} else {
+				str.WriteString("  ")
+			}
+
+			cell := cells[row*n+col]
+			str.WriteString(cell)
+			str.WriteString(strings.Repeat(" ", widths[col]-len(cell)))
+
+			if col%boxWidth == boxWidth-1 {
+				str.WriteByte(' ')
+			}
+		}
+
+		str.WriteString("|\n")
+	}
+
+	border('\'', '\'')
+
+	return str.String()
+}
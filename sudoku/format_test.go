@@ -0,0 +1,131 @@
+package sudoku_test
+
+import (
+	"testing"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+func TestParseRowMajor(t *testing.T) {
+	board, err := sudoku.ParseRowMajor(hardPuzzle)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !board.IsEqual(initRows(hardPuzzle)) || board.RowMajorString() != hardPuzzle {
+		t.Error("The row-major string didn't round-trip")
+	}
+
+	// The same puzzle, as a grid with other blanks and separators.
+	grid := `
+		1 0 0 | 0 0 0 | 0 0 2
+		0 9 0 | 4 0 0 | 0 5 0
+		0 0 6 | 0 0 0 | 7 0 0
+		------+-------+------
+		0 5 0 | 9 0 3 | 0 0 0
+		0 0 0 | 0 7 0 | 0 0 0
+		0 0 0 | 8 5 0 | 0 4 0
+		------+-------+------
+		7 _ _ | _ _ _ | 6 _ _
+		_ 3 _ | _ _ 9 | _ 8 _
+		_ _ 2 | _ _ _ | _ _ 1
+	`
+	board, err = sudoku.ParseRowMajor(grid)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if board.RowMajorString() != hardPuzzle {
+		t.Errorf("Expected %s, got %s", hardPuzzle, board.RowMajorString())
+	}
+
+	s := &sudoku.Sudoku{N: 12, BoxWidth: 3, BoxHeight: 4, Seed: 3}
+	s.Init()
+	s.Fill()
+
+	board, err = sudoku.ParseBoardFormat(s.Format(sudoku.RowMajor), sudoku.RowMajor, 3, 4)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !board.IsEqual(s) || board.String() != s.String() {
+		t.Error("The parsed 12x12 board is not the same as the original one")
+	}
+
+	if _, err := sudoku.ParseRowMajor("1....1.........."); err == nil {
+		t.Error("A board with the same number twice in a box was parsed")
+	}
+
+	if _, err := sudoku.ParseRowMajor("..5..2....4..1.."); err == nil {
+		t.Error("A 4x4 board with a 5 was parsed")
+	}
+
+	if _, err := sudoku.ParseRowMajor("..Z..2....4..1.."); err == nil {
+		t.Error("A board with an invalid symbol was parsed")
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, format := range []sudoku.Format{sudoku.BoxMajor, sudoku.RowMajor} {
+		parsed, err := sudoku.ParseFormat(format.String())
+
+		if err != nil || parsed != format {
+			t.Errorf("Unable to parse the %s format", format)
+		}
+	}
+
+	if _, err := sudoku.ParseFormat("column"); err == nil {
+		t.Error("An unknown format was parsed")
+	}
+}
+
+func TestParseCandidateGrid(t *testing.T) {
+	s := initRows(hardPuzzle)
+	s.EliminateCandidate(0, 1, 3)
+	s.EliminateCandidate(8, 7, 4)
+
+	board, err := sudoku.ParseCandidateGrid(s.CandidateGridString())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !board.IsEqual(s) || board.Candidates(0, 1) != s.Candidates(0, 1) || board.Candidates(8, 7).Has(4) {
+		t.Error("The candidate grid didn't round-trip")
+	}
+
+	for i, set := range board.CandidateGrid() {
+		if set != s.CandidateGrid()[i] {
+			t.Errorf("Expected the candidates %s in r%dc%d, got %s", s.CandidateGrid()[i], i/9+1, i%9+1, set)
+		}
+	}
+
+	// The steps carry on from the eliminations of the grid.
+	steps, _ := s.SolveLogically(false)
+	gridSteps, _ := board.SolveLogically(false)
+
+	if len(steps) != len(gridSteps) {
+		t.Errorf("Expected %d steps, got %d", len(steps), len(gridSteps))
+	}
+
+	grid := `
+		.-----------.---------.
+		| 1    234  | 34  2   |
+		| 3    1    | 4   24  |
+		:-----------+---------:
+		| 24   1    | 2   3   |
+		| 24   3    | 24  1   |
+		'-----------'---------'
+	`
+
+	if _, err := sudoku.ParseCandidateGrid(grid); err == nil {
+		t.Error("A grid with the same number twice in a box was parsed")
+	}
+
+	if _, err := sudoku.ParseCandidateGrid("1 2 3 45\n" + "1234 1234 1234 1234\n"); err == nil {
+		t.Error("A grid with an invalid number of cells was parsed")
+	}
+}
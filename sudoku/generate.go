@@ -0,0 +1,356 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DefaultMaxAttempts is the number of puzzles `GenerateWithOptions` tries when the options
+// don't set it.
+const DefaultMaxAttempts = 100
+
+// ErrAttemptsExhausted is returned when no puzzle meets the requirements within the attempt
+// budget.
+var ErrAttemptsExhausted = errors.New("no puzzle met the requirements")
+
+// GenerateOptions holds the requirements of the puzzles `GenerateWithOptions` generates.
+type GenerateOptions struct {
+	MinDifficulty Difficulty // The easiest difficulty that's accepted.
+	MaxDifficulty Difficulty // The hardest difficulty that's accepted.
+	MinClues      int        // The fewest clues that are accepted; 0 for no limit.
+	MaxClues      int        // The most clues that are accepted; 0 for no limit.
+	MaxAttempts   int        // The number of puzzles to try; `DefaultMaxAttempts` if 0.
+	Minimal       bool       // Whether to empty every clue that isn't needed (see `Minimize`).
+}
+
+// ParseGenerateOptions parses the difficulty and the clue count that a generated puzzle should
+// have (see `GenerateOptions`). Both can be either a single value or a range in the MIN-MAX
+// format (e.g. "medium-expert" and "25-30"), and the clue count can be left empty.
+func ParseGenerateOptions(difficulty, clues string) (*GenerateOptions, error) {
+	opts := &GenerateOptions{}
+	minName, maxName, found := strings.Cut(difficulty, "-")
+
+	if !found {
+		maxName = minName
+	}
+
+	var err error
+
+	if opts.MinDifficulty, err = ParseDifficulty(minName); err != nil {
+		return nil, err
+	}
+
+	if opts.MaxDifficulty, err = ParseDifficulty(maxName); err != nil {
+		return nil, err
+	}
+
+	if opts.MinDifficulty > opts.MaxDifficulty {
+		return nil, fmt.Errorf("invalid difficulty range \"%s\"", difficulty)
+	}
+
+	if clues == "" {
+		return opts, nil
+	}
+
+	minClues, maxClues, found := strings.Cut(clues, "-")
+
+	if !found {
+		maxClues = minClues
+	}
+
+	opts.MinClues, err = strconv.Atoi(minClues)
+
+	if err == nil {
+		opts.MaxClues, err = strconv.Atoi(maxClues)
+	}
+
+	if err != nil || opts.MinClues < 1 || opts.MinClues > opts.MaxClues {
+		return nil, fmt.Errorf("invalid clue range \"%s\"", clues)
+	}
+
+	return opts, nil
+}
+
+// GenerateWithOptions needs to run after `Init`. It fills the board and generates a puzzle out
+// of it, like `Fill` and `GeneratePuzzle` do, until the puzzle meets the requirements of the
+// options. Each attempt moves on to the next seed (through the counter), so the same seed and
+// options always lead to the same puzzle. The board is left with the solution of the puzzle,
+// and the rating of the puzzle is set on both. It returns `ErrAttemptsExhausted` if none of the
+// attempts met the requirements.
+func (s *Sudoku) GenerateWithOptions(opts GenerateOptions) (*Sudoku, error) {
+	return s.generateWithOptions(opts, nil)
+}
+
+// GenerateWithOptionsContext is `GenerateWithOptions`, but it stops with an `AbortError` once the
+// context is done or its budget runs out (see `WithBudget`). The budget covers all the attempts.
+func (s *Sudoku) GenerateWithOptionsContext(ctx context.Context, opts GenerateOptions) (*Sudoku, error) {
+	return s.generateWithOptions(opts, newLimiter(ctx))
+}
+
+// generateWithOptions generates a puzzle that meets the requirements of the options, until the
+// limiter stops it.
+func (s *Sudoku) generateWithOptions(opts GenerateOptions, l *limiter) (*Sudoku, error) {
+	maxAttempts := opts.MaxAttempts
+
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			s.count++
+		}
+
+		s.reseed(s.Seed + s.count)
+
+		if err := s.fillBoard(l); err != nil {
+			return nil, err
+		}
+
+		puzzle, err := s.generatePuzzle(l)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if opts.Minimal {
+			if err := puzzle.minimize(l); err != nil {
+				return nil, err
+			}
+		}
+
+		clues := int(s.N)*int(s.N) - puzzle.CountEmpty()
+
+		if (opts.MinClues > 0 && clues < opts.MinClues) || (opts.MaxClues > 0 && clues > opts.MaxClues) {
+			continue
+		}
+
+		rating, err := puzzle.grade(l)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if rating.Difficulty < opts.MinDifficulty || rating.Difficulty > opts.MaxDifficulty {
+			continue
+		}
+
+		s.Rating = rating
+		puzzle.Rating = rating
+
+		return puzzle, nil
+	}
+
+	return nil, fmt.Errorf("%w after %d attempts", ErrAttemptsExhausted, maxAttempts)
+}
+
+// GenerateFromMask needs to run after `Init`. It generates a puzzle whose clues are exactly the
+// cells marked in a mask (see `ParseClueMask`), which is handy for puzzles whose clues form a
+// shape. Each attempt fills the board, moving on to the next seed each time, and takes the
+// numbers of the clues from it, until they lead to a single solution. Since a filled board
+// hardly ever does on its own, the numbers are otherwise picked so that they do (see
+// `fillMask`), in which case the solution isn't the board that was filled. The board is left
+// with the solution of the puzzle, which has no symmetry. It returns `ErrAttemptsExhausted` if
+// none of the `maxAttempts` attempts, or `DefaultMaxAttempts` if it's 0, led to a puzzle.
+func (s *Sudoku) GenerateFromMask(mask string, maxAttempts int) (*Sudoku, error) {
+	return s.generateFromMask(mask, maxAttempts, nil)
+}
+
+// GenerateFromMaskContext is `GenerateFromMask`, but it stops with an `AbortError` once the
+// context is done or its budget runs out (see `WithBudget`). The budget covers all the attempts.
+func (s *Sudoku) GenerateFromMaskContext(ctx context.Context, mask string, maxAttempts int) (*Sudoku, error) {
+	return s.generateFromMask(mask, maxAttempts, newLimiter(ctx))
+}
+
+// generateFromMask generates a puzzle whose clues are the cells of a mask, until the limiter
+// stops it.
+func (s *Sudoku) generateFromMask(mask string, maxAttempts int, l *limiter) (*Sudoku, error) {
+	n := int(s.N)
+	clues, err := ParseClueMask(n, mask)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			s.count++
+		}
+
+		s.reseed(s.Seed + s.count)
+
+		if err := s.fillBoard(l); err != nil {
+			return nil, err
+		}
+
+		puzzle := s.fillMask(clues, l)
+
+		if err := l.check(); err != nil {
+			return nil, err
+		}
+
+		if puzzle != nil {
+			return puzzle, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w after %d attempts", ErrAttemptsExhausted, maxAttempts)
+}
+
+// maskCountLimit is the number of solutions `pickClues` counts up to when picking a clue.
+const maskCountLimit = 100
+
+// fillMask makes a puzzle out of the clues of a mask. The clues get the numbers of the filled
+// board if those lead to a single solution, but hardly any random board does on a shape of 25-30
+// clues, so otherwise they're picked one by one on an empty puzzle (see `pickClues`). Either
+// way, the puzzle has no symmetry, since its clues follow the mask. It returns the puzzle if
+// it's left with a single solution, which is then written to the board, or nil otherwise (or if
+// the limiter stops it).
+func (s *Sudoku) fillMask(clues []bool, l *limiter) *Sudoku {
+	n := int(s.N)
+	puzzle := &Sudoku{}
+	puzzle.Copy(s)
+	puzzle.Symmetry = NoSymmetry
+	puzzle.SymmetryMask = ""
+
+	for _, box := range puzzle.Board {
+		box.Empty()
+	}
+
+	g := newGrid(puzzle)
+	g.limit = l
+	defer g.release()
+
+	for idx, isClue := range clues {
+		if isClue {
+			g.set(idx, s.GetCell(idx/n, idx%n))
+		}
+	}
+
+	if g.count(2) != 1 && !s.pickClues(g, clues) {
+		return nil
+	}
+
+	if g.exhausted() {
+		return nil
+	}
+
+	g.write(puzzle)
+
+	solution := &Sudoku{}
+	solution.Copy(puzzle)
+	solution.Solve()
+
+	for i, box := range solution.Board {
+		s.Board[i].SetNumbers(box.numbers)
+	}
+
+	return puzzle
+}
+
+// pickClues picks the numbers of the clues of a mask on the grid, in a random order, after
+// emptying them. Each clue gets the number which leaves the fewest solutions, and ties go to
+// the number of the filled board. It returns whether the clues lead to a single solution.
+func (s *Sudoku) pickClues(g *grid, clues []bool) bool {
+	n := int(s.N)
+
+	for idx, isClue := range clues {
+		if isClue {
+			g.unset(idx)
+		}
+	}
+
+	for _, idx := range s.rand.Perm(n * n) {
+		if !clues[idx] || g.exhausted() {
+			continue
+		}
+
+		filled := s.GetCell(idx/n, idx%n)
+		best, bestCount := filled, int64(maskCountLimit+1)
+
+		for _, num := range s.rand.Perm(n) {
+			if g.possibilities(idx)&(1<<(num+1)) == 0 {
+				continue
+			}
+
+			g.set(idx, uint8(num+1))
+			count := g.count(maskCountLimit)
+			g.unset(idx)
+
+			if count > 0 && (count < bestCount || (count == bestCount && uint8(num+1) == filled)) {
+				best, bestCount = uint8(num+1), count
+			}
+		}
+
+		if g.possibilities(idx)&(1<<best) == 0 {
+			return false
+		}
+
+		g.set(idx, best)
+	}
+
+	// The clues rarely pin down a single solution by themselves, so they are changed one at a
+	// time, for as long as that leaves fewer solutions.
+	count := g.count(maskCountLimit)
+
+	for improved := true; count > 1 && improved; {
+		improved = false
+
+		for _, idx := range s.rand.Perm(n * n) {
+			if !clues[idx] || count == 1 {
+				continue
+			}
+
+			best := g.cells[idx]
+			g.unset(idx)
+			possibilities := g.possibilities(idx) &^ (1 << best)
+
+			for possibilities != 0 {
+				num := uint8(bits.TrailingZeros32(possibilities))
+				possibilities &^= 1 << num
+
+				g.set(idx, num)
+				other := g.count(count)
+				g.unset(idx)
+
+				if other > 0 && other < count {
+					best, count, improved = num, other, true
+				}
+			}
+
+			g.set(idx, best)
+		}
+	}
+
+	return count == 1
+}
+
+// ParseClueMask parses a mask of the clues of a board of size `n`. The mask has a character for
+// every cell, row by row, where '.', '0', '_' and '-' mark empty cells and anything else marks a
+// clue. Whitespace is ignored, so the mask can be split into lines.
+func ParseClueMask(n int, mask string) ([]bool, error) {
+	clues := make([]bool, 0, n*n)
+
+	for _, c := range mask {
+		if unicode.IsSpace(c) {
+			continue
+		}
+
+		clues = append(clues, !strings.ContainsRune(".0_-", c))
+	}
+
+	if len(clues) != n*n {
+		return nil, fmt.Errorf("the clue mask has %d cells instead of %d", len(clues), n*n)
+	}
+
+	return clues, nil
+}
@@ -0,0 +1,120 @@
+package sudoku_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+func TestGenerateWithOptions(t *testing.T) {
+	opts := sudoku.GenerateOptions{
+		MinDifficulty: sudoku.Medium,
+		MaxDifficulty: sudoku.Hard,
+		MinClues:      24,
+		MaxClues:      32,
+	}
+
+	board := &sudoku.Sudoku{Seed: 42}
+	board.Init()
+	puzzle, err := board.GenerateWithOptions(opts)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if puzzle.Rating == nil || puzzle.Rating.Difficulty < opts.MinDifficulty || puzzle.Rating.Difficulty > opts.MaxDifficulty {
+		t.Fatalf("The puzzle's difficulty is outside of the band: %v", puzzle.Rating)
+	}
+
+	if clues := 81 - puzzle.CountEmpty(); clues < opts.MinClues || clues > opts.MaxClues {
+		t.Errorf("The puzzle has %d clues", clues)
+	}
+
+	if puzzle.CountSolutions() != 1 {
+		t.Error("The puzzle doesn't have a unique solution")
+	}
+
+	puzzleStr := puzzle.String()
+
+	// The board holds the solution of the puzzle.
+	puzzle.Solve()
+
+	if !puzzle.IsEqual(board) {
+		t.Error("The board isn't the solution of the puzzle")
+	}
+
+	// The same seed and options lead to the same puzzle.
+	other := &sudoku.Sudoku{Seed: 42}
+	other.Init()
+	otherPuzzle, err := other.GenerateWithOptions(opts)
+
+	if err != nil || !other.IsEqual(board) || otherPuzzle.String() != puzzleStr {
+		t.Error("The generation isn't deterministic")
+	}
+}
+
+func TestGenerateWithOptionsExhausted(t *testing.T) {
+	board := &sudoku.Sudoku{Seed: 1}
+	board.Init()
+	_, err := board.GenerateWithOptions(sudoku.GenerateOptions{
+		MinDifficulty: sudoku.Easy,
+		MaxDifficulty: sudoku.Extreme,
+		MinClues:      70,
+		MaxAttempts:   3,
+	})
+
+	if !errors.Is(err, sudoku.ErrAttemptsExhausted) {
+		t.Errorf("Expected the attempts to run out, got %v", err)
+	}
+}
+
+func TestGenerateFromMask(t *testing.T) {
+	heart := `
+		.xx...xx.
+		x..x.x..x
+		x...x...x
+		x.......x
+		.x.x.x.x.
+		..x...x..
+		...x.x...
+		..x.x.x..
+		....x....`
+	clues, err := sudoku.ParseClueMask(9, heart)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	board := &sudoku.Sudoku{Seed: 11}
+	board.Init()
+	puzzle, err := board.GenerateFromMask(heart, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for idx, isClue := range clues {
+		if (puzzle.GetCell(idx/9, idx%9) != 0) != isClue {
+			t.Fatalf("The puzzle doesn't follow the mask at r%dc%d", idx/9+1, idx%9+1)
+		}
+	}
+
+	if puzzle.CountSolutions() != 1 {
+		t.Error("The puzzle doesn't have a unique solution")
+	}
+
+	if puzzle.Symmetry != sudoku.NoSymmetry {
+		t.Errorf("Expected a puzzle without a symmetry, got %s", puzzle.Symmetry)
+	}
+
+	puzzle.Solve()
+
+	if !puzzle.IsEqual(board) {
+		t.Error("The board isn't the solution of the puzzle")
+	}
+
+	if _, err := board.GenerateFromMask("x..x", 0); err == nil {
+		t.Error("A short mask was accepted")
+	}
+}
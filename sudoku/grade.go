@@ -0,0 +1,151 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Difficulty is how hard a puzzle is for a person to solve.
+type Difficulty int
+
+const (
+	Easy Difficulty = iota
+	Medium
+	Hard
+	Expert
+	Extreme
+)
+
+var difficultyNames = []string{"Easy", "Medium", "Hard", "Expert", "Extreme"}
+
+// difficultyScores holds the highest score of each difficulty, apart from `Extreme` which
+// covers everything above them. Easy puzzles only need hidden singles, medium ones go up to
+// naked pairs and hard ones up to hidden triples, while expert puzzles need wings, quads,
+// coloring or chains. Anything that requires guessing is extreme.
+var difficultyScores = []float64{2.0, 3.0, 4.0, 7.0}
+
+// String returns the name of the difficulty.
+func (d Difficulty) String() string {
+	if d < Easy || d > Extreme {
+		return fmt.Sprintf("Difficulty(%d)", int(d))
+	}
+
+	return difficultyNames[d]
+}
+
+// MarshalText is used by the JSON module to write the difficulty by its name.
+func (d Difficulty) MarshalText() ([]byte, error) {
+	if d < Easy || d > Extreme {
+		return nil, fmt.Errorf("invalid difficulty %d", int(d))
+	}
+
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText is used by the JSON module to read the difficulty from its name.
+func (d *Difficulty) UnmarshalText(text []byte) error {
+	difficulty, err := ParseDifficulty(string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*d = difficulty
+
+	return nil
+}
+
+// ParseDifficulty returns the difficulty with a specific name, regardless of its case.
+func ParseDifficulty(name string) (Difficulty, error) {
+	for i, difficultyName := range difficultyNames {
+		if strings.EqualFold(name, difficultyName) {
+			return Difficulty(i), nil
+		}
+	}
+
+	return Easy, fmt.Errorf("unknown difficulty \"%s\"", name)
+}
+
+// ErrNoSolution is returned when a puzzle can't be solved.
+var ErrNoSolution = errors.New("the puzzle has no solution")
+
+// Rating describes how hard a puzzle is, based on the techniques it takes to solve it.
+type Rating struct {
+	Difficulty Difficulty        `json:"difficulty"`
+	Score      float64           `json:"score"` // The rating of the hardest technique.
+	Steps      int               `json:"steps"`
+	Techniques map[Technique]int `json:"techniques"` // How many times each technique was used.
+}
+
+// String returns a short description of the rating, like "Hard (3.4)".
+func (r *Rating) String() string {
+	return fmt.Sprintf("%s (%.1f)", r.Difficulty, r.Score)
+}
+
+// Grade rates the puzzle by solving a copy of it logically. The score of the puzzle, similar to
+// the Sudoku Explainer rating, is the rating of the hardest technique that was needed, and it
+// decides the difficulty. It returns an error if the puzzle has no solution.
+func (s *Sudoku) Grade() (*Rating, error) {
+	return s.grade(nil)
+}
+
+// GradeContext is `Grade`, but it stops with an `AbortError` once the context is done or its
+// budget runs out (see `WithBudget`).
+func (s *Sudoku) GradeContext(ctx context.Context) (*Rating, error) {
+	return s.grade(newLimiter(ctx))
+}
+
+// grade rates the puzzle, until the limiter stops it.
+func (s *Sudoku) grade(l *limiter) (*Rating, error) {
+	puzzle := &Sudoku{}
+	puzzle.Copy(s)
+
+	// The candidates that were eliminated by hand don't make the puzzle any easier.
+	puzzle.ResetCandidates()
+
+	steps, solved := puzzle.solveLogically(true, nil, l)
+
+	if l.stopped() {
+		return nil, l.err
+	}
+
+	if !solved {
+		return nil, ErrNoSolution
+	}
+
+	rating := &Rating{
+		Steps:      len(steps),
+		Techniques: make(map[Technique]int),
+	}
+
+	for _, step := range steps {
+		rating.Techniques[step.Technique]++
+		if score := techniqueRating(step.Technique); score > rating.Score {
+			rating.Score = score
+		}
+	}
+
+	rating.Difficulty = Extreme
+
+	for i, score := range difficultyScores {
+		if rating.Score <= score {
+			rating.Difficulty = Difficulty(i)
+			break
+		}
+	}
+
+	return rating, nil
+}
+
+// techniqueRating returns the rating of a technique.
+func techniqueRating(technique Technique) float64 {
+	for _, t := range techniques {
+		if t.technique == technique {
+			return t.rating
+		}
+	}
+
+	return guessRating
+}
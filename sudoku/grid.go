@@ -0,0 +1,340 @@
+package sudoku
+
+import (
+	"math/bits"
+	"sync"
+)
+
+// layout holds the tables of a board geometry which the solvers look up, so that they don't
+// need to be worked out every time a board is solved.
+type layout struct {
+	boxOf   []int    // The box of each cell.
+	cellOf  []int    // The cell of each position of each box, box by box.
+	units   [][]int  // The cells of the rows, then the columns and then the boxes.
+	unitsOf [][3]int // The row, column and box unit of each cell.
+	peers   [][]int  // The other cells that share a unit with each cell.
+}
+
+// layouts caches the layout of every board geometry which has been used so far.
+var layouts sync.Map
+
+// layoutOf returns the layout of the geometry of a board.
+func layoutOf(s *Sudoku) *layout {
+	key := [3]uint8{s.N, s.BoxWidth, s.BoxHeight}
+
+	if l, ok := layouts.Load(key); ok {
+		return l.(*layout)
+	}
+
+	n := int(s.N)
+	l := &layout{
+		boxOf:   make([]int, n*n),
+		cellOf:  make([]int, n*n),
+		units:   make([][]int, n*3),
+		unitsOf: make([][3]int, n*n),
+		peers:   make([][]int, n*n),
+	}
+
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			row, col := s.rowColFromBoxPos(i, j)
+			l.boxOf[row*n+col] = i
+			l.cellOf[i*n+j] = row*n + col
+		}
+	}
+
+	// Every row, column and box is a unit in which each number needs to appear once.
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			l.units[i] = append(l.units[i], i*n+j)
+			l.units[n+i] = append(l.units[n+i], j*n+i)
+		}
+	}
+
+	for idx, box := range l.boxOf {
+		l.units[n*2+box] = append(l.units[n*2+box], idx)
+		l.unitsOf[idx] = [3]int{idx / n, n + idx%n, n*2 + box}
+	}
+
+	for idx := range l.peers {
+		seen := make(map[int]bool)
+
+		for _, u := range l.unitsOf[idx] {
+			for _, peer := range l.units[u] {
+				if peer != idx && !seen[peer] {
+					seen[peer] = true
+					l.peers[idx] = append(l.peers[idx], peer)
+				}
+			}
+		}
+	}
+
+	actual, _ := layouts.LoadOrStore(key, l)
+
+	return actual.(*layout)
+}
+
+// grid is a flat copy of a board which is used when searching for solutions. The cells are
+// stored row by row and the numbers used in every row, column and box are kept as bits, so that
+// the possibilities of a cell can be found without going through the boxes. The slices point to
+// the buffers of the grid, so that it takes a single allocation, and the searches put their
+// grids back (see `release`) for the next ones to reuse.
+type grid struct {
+	*layout
+	n     int
+	all   uint32 // The bits of all the numbers.
+	cells []uint8
+	used  []uint32 // The numbers of each unit, in the order of `units`.
+
+	// The search gives up once it has visited more than `maxNodes` (if set).
+	nodes    int64
+	maxNodes int64
+
+	// limit stops the search of a call with a context (see `limiter`).
+	limit *limiter
+
+	// A board with a number twice in a unit has no solutions.
+	invalid bool
+
+	cellBuffer [MaxN * MaxN]uint8
+	maskBuffer [MaxN * 3]uint32
+}
+
+// grids holds the grids which were released, so that a search doesn't need to allocate one.
+var grids = sync.Pool{New: func() any { return new(grid) }}
+
+// newGrid creates a grid out of a board.
+func newGrid(s *Sudoku) *grid {
+	n := int(s.N)
+	g := grids.Get().(*grid)
+	*g = grid{layout: layoutOf(s), n: n, all: uint32(1<<(n+1)) - 2}
+	g.cells = g.cellBuffer[:n*n]
+	g.used = g.maskBuffer[:n*3]
+
+	for i, box := range s.Board {
+		for j, num := range box.numbers {
+			if num == 0 {
+				continue
+			}
+
+			idx := g.cellOf[i*n+j]
+			g.invalid = g.invalid || g.possibilities(idx)&(1<<num) == 0
+			g.set(idx, num)
+		}
+	}
+
+	return g
+}
+
+// release puts the grid back, for the next search to use. It can't be used afterwards.
+func (g *grid) release() {
+	grids.Put(g)
+}
+
+// set places a number in a cell.
+func (g *grid) set(idx int, num uint8) {
+	u := &g.unitsOf[idx]
+	g.cells[idx] = num
+	g.used[u[0]] |= 1 << num
+	g.used[u[1]] |= 1 << num
+	g.used[u[2]] |= 1 << num
+}
+
+// unset empties a cell.
+func (g *grid) unset(idx int) {
+	num := g.cells[idx]
+	u := &g.unitsOf[idx]
+	g.cells[idx] = 0
+	g.used[u[0]] &^= 1 << num
+	g.used[u[1]] &^= 1 << num
+	g.used[u[2]] &^= 1 << num
+}
+
+// possibilities returns the numbers that can go in a cell as bits.
+func (g *grid) possibilities(idx int) uint32 {
+	u := &g.unitsOf[idx]
+
+	return g.all &^ (g.used[u[0]] | g.used[u[1]] | g.used[u[2]])
+}
+
+// write copies the numbers of the grid back to a board.
+func (g *grid) write(s *Sudoku) {
+	for i, box := range s.Board {
+		box.mask = 0
+
+		for j := range box.numbers {
+			num := g.cells[g.cellOf[i*g.n+j]]
+			box.numbers[j] = num
+
+			if num != 0 {
+				box.mask |= 1 << num
+			}
+		}
+	}
+}
+
+// mostConstrained returns the first empty cell with the fewest possibilities, along with them,
+// or -1 if the grid is full.
+func (g *grid) mostConstrained() (int, uint32) {
+	best := -1
+	bestCount := g.n + 1
+	var bestPossibilities uint32
+
+	for idx, num := range g.cells {
+		if num != 0 {
+			continue
+		}
+
+		possibilities := g.possibilities(idx)
+		count := bits.OnesCount32(possibilities)
+
+		if count < bestCount {
+			best, bestCount, bestPossibilities = idx, count, possibilities
+
+			if count == 0 {
+				break
+			}
+		}
+	}
+
+	return best, bestPossibilities
+}
+
+// count returns the number of solutions of the grid. It stops once it has found `limit`
+// solutions, unless `limit` is 0.
+func (g *grid) count(limit int64) int64 {
+	var count int64
+
+	g.search(func() bool {
+		count++
+
+		return limit > 0 && count >= limit
+	})
+
+	return count
+}
+
+// exhausted returns whether the search has run out of nodes, or has to stop for its limiter.
+func (g *grid) exhausted() bool {
+	return (g.maxNodes > 0 && g.nodes > g.maxNodes) || g.limit.stopped()
+}
+
+// hasOtherSolution returns whether the grid can be solved with a number other than `num` in the
+// empty cell `idx`.
+func (g *grid) hasOtherSolution(idx int, num uint8) bool {
+	possibilities := g.possibilities(idx) &^ (1 << num)
+
+	for possibilities != 0 {
+		other := uint8(bits.TrailingZeros32(possibilities))
+		possibilities &^= 1 << other
+
+		g.set(idx, other)
+		found := g.search(func() bool {
+			return true
+		})
+		g.unset(idx)
+
+		if found {
+			return true
+		}
+	}
+
+	return false
+}
+
+// search goes through the solutions of the grid and calls `found` for each one of them, while
+// the grid holds it. The search stops, returning true, as soon as `found` returns true.
+func (g *grid) search(found func() bool) bool {
+	if g.invalid {
+		return false
+	}
+
+	g.nodes++
+	g.limit.visit()
+
+	if g.exhausted() {
+		return true
+	}
+
+	best := -1
+	bestCount := g.n + 1
+	var bestPossibilities uint32
+
+	// Always go for the most constrained cell, since it leads to the fewest branches.
+	for idx, num := range g.cells {
+		if num != 0 {
+			continue
+		}
+
+		possibilities := g.possibilities(idx)
+		count := bits.OnesCount32(possibilities)
+
+		if count < bestCount {
+			best, bestCount, bestPossibilities = idx, count, possibilities
+
+			if count <= 1 {
+				break
+			}
+		}
+	}
+
+	if best < 0 {
+		return found()
+	}
+
+	// A number which fits in only one cell of a unit has to go there, so it's even better than
+	// a cell with two possibilities.
+	if bestCount > 1 {
+		for _, unit := range g.units {
+			var once, twice, used uint32
+
+			for _, idx := range unit {
+				if g.cells[idx] != 0 {
+					used |= 1 << g.cells[idx]
+					continue
+				}
+
+				possibilities := g.possibilities(idx)
+				twice |= once & possibilities
+				once |= possibilities
+			}
+
+			// If a number can't go anywhere in the unit, this is a dead end.
+			if g.all&^(once|used) != 0 {
+				return false
+			}
+
+			single := once &^ twice
+
+			if single == 0 {
+				continue
+			}
+
+			num := uint8(bits.TrailingZeros32(single))
+
+			for _, idx := range unit {
+				if g.cells[idx] == 0 && g.possibilities(idx)&(1<<num) != 0 {
+					best, bestPossibilities = idx, 1<<num
+					break
+				}
+			}
+
+			break
+		}
+	}
+
+	for bestPossibilities != 0 {
+		num := uint8(bits.TrailingZeros32(bestPossibilities))
+		bestPossibilities &^= 1 << num
+
+		g.set(best, num)
+		stop := g.search(found)
+		g.unset(best)
+
+		if stop {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,133 @@
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrSolved is returned when a hint is asked for a puzzle which is already solved.
+var ErrSolved = errors.New("the puzzle is already solved")
+
+// HintLevel is how much a hint gives away, from where to look to the whole step.
+type HintLevel int
+
+const (
+	HintArea      HintLevel = iota + 1 // Where to look, like "Look at box 5".
+	HintTechnique                      // The technique and where to use it.
+	HintStep                           // The numbers to place or to eliminate.
+)
+
+// Hint is the easiest step that can be taken next, along with the part of the board it's in.
+type Hint struct {
+	Step
+	Area string `json:"area"` // The unit or the cells of the step, like "box 5".
+}
+
+// Hint returns the easiest step that can be taken next on the board, as the logical solver
+// would (see `SolveLogically`). If no technique makes progress, the step reveals the number of
+// the cell with the fewest candidates. The candidates that were eliminated by hand are taken
+// into account, so that the hints move on once their eliminations are applied, apart from the
+// ones that would rule out the number of the solution. It returns `ErrSolved` if there are no
+// empty cells and `ErrNoSolution` if the numbers on the board lead nowhere.
+func (s *Sudoku) Hint() (*Hint, error) {
+	solution := &Sudoku{}
+	solution.Copy(s)
+
+	if !solution.Solve() {
+		return nil, ErrNoSolution
+	}
+
+	l := newLogicGrid(s)
+
+	if l.isSolved() {
+		return nil, ErrSolved
+	}
+
+	for idx := range l.candidates {
+		l.candidates[idx] &^= uint32(s.eliminated(idx).Remove(solution.GetCell(idx/l.n, idx%l.n)))
+	}
+
+	step := l.nextStep()
+
+	if step == nil {
+		step = l.guess(solution)
+	}
+
+	return &Hint{Step: *step, Area: l.area(step)}, nil
+}
+
+// Text returns the hint as a sentence, giving away as much as the level allows.
+func (h *Hint) Text(level HintLevel) string {
+	switch {
+	case level <= HintArea:
+		return fmt.Sprintf("Look at %s.", h.Area)
+	case level == HintTechnique && h.Technique == Guess:
+		return fmt.Sprintf("No technique makes progress, so a number in %s has to be revealed.", h.Area)
+	case level == HintTechnique:
+		return fmt.Sprintf("There's a %s in %s.", h.Technique, h.Area)
+	}
+
+	actions := make([]string, 0, 2)
+
+	for _, p := range h.Placements {
+		actions = append(actions, fmt.Sprintf("put %c in %s", Symbol(p.Value), p.Cell))
+	}
+
+	// The eliminations are grouped by number, like "remove 3 from r1c1, r1c2".
+	cells := make(map[uint8][]Cell)
+	numbers := make([]uint8, 0)
+
+	for _, e := range h.Eliminations {
+		if _, ok := cells[e.Value]; !ok {
+			numbers = append(numbers, e.Value)
+		}
+
+		cells[e.Value] = append(cells[e.Value], e.Cell)
+	}
+
+	for _, num := range numbers {
+		actions = append(actions, fmt.Sprintf("remove %c from %s", Symbol(num), joinCells(cells[num])))
+	}
+
+	text := strings.Join(actions, " and ")
+
+	if len(text) > 0 {
+		text = strings.ToUpper(text[:1]) + text[1:]
+	}
+
+	return fmt.Sprintf("%s (%s: %s).", text, h.Technique, h.Description)
+}
+
+// area returns the part of the board a step is in: the unit it was found in, or else the box,
+// row or column that all of its cells share, or else the cells themselves. A single cell gets
+// its box, so that the area doesn't give the cell away.
+func (l *logicGrid) area(step *Step) string {
+	if step.area != "" {
+		return step.area
+	}
+
+	if len(step.Cells) == 0 {
+		return "the board"
+	}
+
+	// Boxes come first, since that's where people usually look.
+	first := l.unitsOf[l.index(step.Cells[0])]
+
+	for _, i := range []int{2, 0, 1} {
+		shared := true
+
+		for _, c := range step.Cells[1:] {
+			if l.unitsOf[l.index(c)][i] != first[i] {
+				shared = false
+				break
+			}
+		}
+
+		if shared {
+			return l.unitName(first[i])
+		}
+	}
+
+	return joinCells(step.Cells)
+}
@@ -0,0 +1,76 @@
+package sudoku_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+func TestHint(t *testing.T) {
+	hint, err := initSudoku().Hint()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if text := hint.Text(sudoku.HintArea); !strings.HasPrefix(text, "Look at ") || strings.Contains(text, string(hint.Technique)) {
+		t.Errorf("The first hint level gives too much away: %s", text)
+	}
+
+	if text := hint.Text(sudoku.HintTechnique); !strings.Contains(text, string(hint.Technique)) {
+		t.Errorf("The second hint level doesn't name the technique: %s", text)
+	}
+
+	// Following the hints, eliminations and guesses included, leads to the solution.
+	s := initRows(hardPuzzle)
+	solution := initRows(hardPuzzle)
+	solution.Solve()
+
+	for i := 0; i < 500; i++ {
+		hint, err = s.Hint()
+
+		if err != nil {
+			break
+		}
+
+		if hint.Text(sudoku.HintStep) == "" {
+			t.Fatal("The hint has no text")
+		}
+
+		for _, p := range hint.Placements {
+			if p.Value != solution.GetCell(p.Row, p.Col) {
+				t.Fatalf("The hint places a wrong number: %s", p)
+			}
+
+			s.SetCell(p.Row, p.Col, p.Value)
+		}
+
+		for _, e := range hint.Eliminations {
+			s.EliminateCandidate(e.Row, e.Col, e.Value)
+		}
+	}
+
+	if err != sudoku.ErrSolved || !s.IsEqual(solution) {
+		t.Fatalf("The hints didn't lead to the solution: %v", err)
+	}
+
+	broken := initSudoku()
+	broken.SetCell(0, 3, 7)
+
+	if _, err := broken.Hint(); err != sudoku.ErrNoSolution {
+		t.Errorf("Expected a broken board to have no hints, got %v", err)
+	}
+
+	// Only 9 is left for r1c1.
+	single := initRows(".1234...." + strings.Repeat(".........", 2) + "5........6........7........8........" + strings.Repeat(".........", 2))
+	hint, err = single.Hint()
+
+	if err != nil || hint.Technique != sudoku.NakedSingle {
+		t.Fatalf("Expected a naked single, got %v (%v)", hint, err)
+	}
+
+	if text := hint.Text(sudoku.HintArea); text != "Look at box 1." {
+		t.Errorf("The first hint level of a naked single gives the cell away: %s", text)
+	}
+}
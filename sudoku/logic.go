@@ -0,0 +1,380 @@
+package sudoku
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// Technique is the name of a technique which is used to solve a puzzle logically.
+type Technique string
+
+const (
+	NakedSingle      Technique = "Naked Single"
+	HiddenSingle     Technique = "Hidden Single"
+	NakedPair        Technique = "Naked Pair"
+	NakedTriple      Technique = "Naked Triple"
+	NakedQuad        Technique = "Naked Quad"
+	HiddenPair       Technique = "Hidden Pair"
+	HiddenTriple     Technique = "Hidden Triple"
+	HiddenQuad       Technique = "Hidden Quad"
+	PointingPair     Technique = "Pointing Pair"
+	BoxLineReduction Technique = "Box/Line Reduction"
+	XWing            Technique = "X-Wing"
+	Swordfish        Technique = "Swordfish"
+	Jellyfish        Technique = "Jellyfish"
+	XYWing           Technique = "XY-Wing"
+	XYZWing          Technique = "XYZ-Wing"
+	SimpleColoring   Technique = "Simple Coloring"
+	XChain           Technique = "X-Chain"
+	Guess            Technique = "Guess"
+)
+
+// techniques holds the techniques of the logical solver, from the easiest to the hardest, along
+// with their rating on a scale similar to the Sudoku Explainer one. The solver always goes for the
+// easiest technique that makes progress.
+var techniques = []struct {
+	technique Technique
+	rating    float64
+	find      func(l *logicGrid) *Step
+}{
+	{HiddenSingle, 1.5, (*logicGrid).findHiddenSingle},
+	{NakedSingle, 2.3, (*logicGrid).findNakedSingle},
+	{PointingPair, 2.6, (*logicGrid).findPointing},
+	{BoxLineReduction, 2.8, (*logicGrid).findBoxLineReduction},
+	{NakedPair, 3.0, func(l *logicGrid) *Step { return l.findNakedSubset(2) }},
+	{XWing, 3.2, func(l *logicGrid) *Step { return l.findFish(2) }},
+	{HiddenPair, 3.4, func(l *logicGrid) *Step { return l.findHiddenSubset(2) }},
+	{NakedTriple, 3.6, func(l *logicGrid) *Step { return l.findNakedSubset(3) }},
+	{Swordfish, 3.8, func(l *logicGrid) *Step { return l.findFish(3) }},
+	{HiddenTriple, 4.0, func(l *logicGrid) *Step { return l.findHiddenSubset(3) }},
+	{XYWing, 4.2, (*logicGrid).findXYWing},
+	{XYZWing, 4.4, (*logicGrid).findXYZWing},
+	{NakedQuad, 5.0, func(l *logicGrid) *Step { return l.findNakedSubset(4) }},
+	{Jellyfish, 5.2, func(l *logicGrid) *Step { return l.findFish(4) }},
+	{HiddenQuad, 5.4, func(l *logicGrid) *Step { return l.findHiddenSubset(4) }},
+	{SimpleColoring, 6.2, (*logicGrid).findSimpleColoring},
+	{XChain, 6.6, (*logicGrid).findXChain},
+}
+
+// guessRating is the rating of a guess, which is harder than any of the techniques.
+const guessRating = 9.0
+
+// Cell points to a cell of the board. Both the row and the column start from 0.
+type Cell struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
+
+// String returns the cell in the usual "r1c1" notation, where rows and columns start from 1.
+func (c Cell) String() string {
+	return fmt.Sprintf("r%dc%d", c.Row+1, c.Col+1)
+}
+
+// Candidate is a number which may go in a cell.
+type Candidate struct {
+	Cell
+	Value uint8 `json:"value"`
+}
+
+// String returns the candidate in the "r1c1=5" notation.
+func (c Candidate) String() string {
+	return fmt.Sprintf("%s=%c", c.Cell, Symbol(c.Value))
+}
+
+// Step is a single deduction of the logical solver. A step either places numbers in cells or
+// eliminates candidates from them, based on the pattern formed by `Cells`.
+type Step struct {
+	Technique    Technique   `json:"technique"`
+	Cells        []Cell      `json:"cells"`
+	Placements   []Candidate `json:"placements,omitempty"`
+	Eliminations []Candidate `json:"eliminations,omitempty"`
+	Description  string      `json:"description"`
+
+	// area is the unit the step is found in, if it's not the one that all of `Cells` share.
+	area string
+}
+
+// SolveLogically solves the puzzle the way a person would, by applying one technique at a time
+// and always going for the easiest one that makes progress. It returns the steps it took and
+// whether the puzzle was solved. If it gets stuck, it gives up, unless `allowGuessing` is set,
+// in which case it guesses the number of the cell with the fewest candidates and carries on.
+// The candidates that were eliminated by hand stay eliminated, so that it can carry on from a
+// position that's partly solved. The board is left with all the numbers that were placed.
+func (s *Sudoku) SolveLogically(allowGuessing bool) ([]Step, bool) {
+	return s.solveLogically(allowGuessing, nil, nil)
+}
+
+// solveLogically does the work of `SolveLogically`, until the limiter stops it, which counts
+// every step as a node. It calls `applied`, if it's set, after each step with the candidates of
+// the grid from right before it.
+func (s *Sudoku) solveLogically(allowGuessing bool, applied func(l *logicGrid, step *Step, before []uint32), lim *limiter) ([]Step, bool) {
+	l := newLogicGrid(s)
+	steps := make([]Step, 0)
+	var solution *Sudoku
+	var before []uint32
+
+	for idx := range l.candidates {
+		l.candidates[idx] &^= uint32(s.eliminated(idx))
+	}
+
+	for !l.isSolved() && !l.isBroken() {
+		lim.visit()
+
+		if lim.check() != nil {
+			break
+		}
+
+		step := l.nextStep()
+
+		if step == nil {
+			if !allowGuessing {
+				break
+			}
+
+			if solution == nil {
+				solution = &Sudoku{}
+				solution.Copy(s)
+
+				if solved, _ := solution.solve(lim); !solved {
+					break
+				}
+			}
+
+			step = l.guess(solution)
+		}
+
+		if applied != nil {
+			before = append(before[:0], l.candidates...)
+		}
+
+		l.apply(step)
+		steps = append(steps, *step)
+
+		if applied != nil {
+			applied(l, step, before)
+		}
+	}
+
+	l.write(s)
+
+	return steps, l.isSolved()
+}
+
+// logicGrid keeps the numbers and the candidates of every cell, row by row, for the logical
+// solver.
+type logicGrid struct {
+	n          int
+	cells      []uint8
+	candidates []uint32
+	units      [][]int
+	unitsOf    [][3]int
+	peers      [][]int
+}
+
+// newLogicGrid creates the logical solver's grid out of a board, with all the candidates that
+// the numbers on the board allow.
+func newLogicGrid(s *Sudoku) *logicGrid {
+	g := newGrid(s)
+	l := &logicGrid{
+		n:          g.n,
+		cells:      g.cells,
+		candidates: make([]uint32, len(g.cells)),
+		units:      g.units,
+		unitsOf:    g.unitsOf,
+		peers:      g.peers,
+	}
+
+	for idx, num := range l.cells {
+		if num == 0 {
+			l.candidates[idx] = g.possibilities(idx)
+		}
+	}
+
+	return l
+}
+
+// nextStep returns the easiest step that can be taken, or nil if the solver is stuck.
+func (l *logicGrid) nextStep() *Step {
+	for _, t := range techniques {
+		if step := t.find(l); step != nil {
+			return step
+		}
+	}
+
+	return nil
+}
+
+// guess places the number of the solution in the empty cell with the fewest candidates.
+func (l *logicGrid) guess(solution *Sudoku) *Step {
+	best := -1
+
+	for idx, num := range l.cells {
+		if num == 0 && (best < 0 || bits.OnesCount32(l.candidates[idx]) < bits.OnesCount32(l.candidates[best])) {
+			best = idx
+		}
+	}
+
+	placement := l.candidate(best, solution.GetCell(best/l.n, best%l.n))
+
+	return &Step{
+		Technique:   Guess,
+		Cells:       []Cell{placement.Cell},
+		Placements:  []Candidate{placement},
+		Description: fmt.Sprintf("No technique makes progress, so %s is guessed", placement),
+	}
+}
+
+// apply places the numbers and eliminates the candidates of a step.
+func (l *logicGrid) apply(step *Step) {
+	for _, p := range step.Placements {
+		l.place(l.index(p.Cell), p.Value)
+	}
+
+	for _, e := range step.Eliminations {
+		l.candidates[l.index(e.Cell)] &^= 1 << e.Value
+	}
+}
+
+// place puts a number in a cell and removes it from the candidates of the cell's peers.
+func (l *logicGrid) place(idx int, num uint8) {
+	l.cells[idx] = num
+	l.candidates[idx] = 0
+
+	for _, peer := range l.peers[idx] {
+		l.candidates[peer] &^= 1 << num
+	}
+}
+
+// write copies the numbers of the grid back to a board.
+func (l *logicGrid) write(s *Sudoku) {
+	for idx, num := range l.cells {
+		s.SetCell(idx/l.n, idx%l.n, num)
+	}
+}
+
+// isSolved returns whether all the cells have a number.
+func (l *logicGrid) isSolved() bool {
+	for _, num := range l.cells {
+		if num == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isBroken returns whether an empty cell has run out of candidates, which only happens when the
+// puzzle has no solution.
+func (l *logicGrid) isBroken() bool {
+	for idx, num := range l.cells {
+		if num == 0 && l.candidates[idx] == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sees returns whether two different cells share a unit.
+func (l *logicGrid) sees(a, b int) bool {
+	if a == b {
+		return false
+	}
+
+	for i := 0; i < 3; i++ {
+		if l.unitsOf[a][i] == l.unitsOf[b][i] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// index returns the index of a cell in the grid.
+func (l *logicGrid) index(c Cell) int {
+	return c.Row*l.n + c.Col
+}
+
+// cell returns the cell of an index in the grid.
+func (l *logicGrid) cell(idx int) Cell {
+	return Cell{Row: idx / l.n, Col: idx % l.n}
+}
+
+// candidate returns a candidate for the cell of an index in the grid.
+func (l *logicGrid) candidate(idx int, num uint8) Candidate {
+	return Candidate{Cell: l.cell(idx), Value: num}
+}
+
+// cellList returns the cells of a list of indices.
+func (l *logicGrid) cellList(indices []int) []Cell {
+	cells := make([]Cell, len(indices))
+
+	for i, idx := range indices {
+		cells[i] = l.cell(idx)
+	}
+
+	return cells
+}
+
+// unitName returns a readable name for a unit, like "row 3" or "box 5".
+func (l *logicGrid) unitName(u int) string {
+	switch u / l.n {
+	case 0:
+		return fmt.Sprintf("row %d", u%l.n+1)
+	case 1:
+		return fmt.Sprintf("column %d", u%l.n+1)
+	default:
+		return fmt.Sprintf("box %d", u%l.n+1)
+	}
+}
+
+// joinCells returns a readable list of cells, like "r1c1, r1c2".
+func joinCells(cells []Cell) string {
+	names := make([]string, len(cells))
+
+	for i, c := range cells {
+		names[i] = c.String()
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// joinNumbers returns a readable list of the numbers in a set of bits, like "3/7".
+func joinNumbers(set uint32) string {
+	names := make([]string, 0)
+
+	for set != 0 {
+		num := bits.TrailingZeros32(set)
+		set &^= 1 << num
+		names = append(names, string(Symbol(uint8(num))))
+	}
+
+	return strings.Join(names, "/")
+}
+
+// combinations calls `fn` with every combination of `k` indices out of `n`, until it returns
+// true. It returns whether `fn` did.
+func combinations(n, k int, fn func(combo []int) bool) bool {
+	combo := make([]int, k)
+
+	var walk func(start, depth int) bool
+
+	walk = func(start, depth int) bool {
+		if depth == k {
+			return fn(combo)
+		}
+
+		for i := start; i <= n-(k-depth); i++ {
+			combo[depth] = i
+
+			if walk(i+1, depth+1) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return walk(0, 0)
+}
@@ -0,0 +1,121 @@
+package sudoku_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+// hardPuzzle is a puzzle, row by row, which can't be solved with the logical techniques alone.
+const hardPuzzle = "1.......2.9.4...5...6...7...5.9.3.......7.......85..4.7.....6...3...9.8...2.....1"
+
+func initRows(rows string) *sudoku.Sudoku {
+	s := &sudoku.Sudoku{}
+	s.Init()
+
+	for i, c := range rows {
+		s.SetCell(i/9, i%9, sudoku.ParseSymbol(c))
+	}
+
+	return s
+}
+
+func TestSolveLogically(t *testing.T) {
+	s := initSudoku()
+	solution := initSudoku()
+	solution.Solve()
+
+	steps, solved := s.SolveLogically(false)
+
+	if !solved || !s.IsEqual(solution) {
+		t.Fatal("Unable to solve sudoku logically")
+	}
+
+	placed := 0
+
+	for _, step := range steps {
+		if step.Technique == sudoku.Guess {
+			t.Error("The solver guessed without being allowed to")
+		}
+
+		if step.Description == "" || len(step.Cells) == 0 {
+			t.Errorf("The %s step is missing its description or cells", step.Technique)
+		}
+
+		placed += len(step.Placements)
+	}
+
+	if placed != initSudoku().CountEmpty() {
+		t.Errorf("The steps placed %d numbers instead of %d", placed, initSudoku().CountEmpty())
+	}
+}
+
+func TestSolveLogicallyGuessing(t *testing.T) {
+	s := initRows(hardPuzzle)
+
+	if _, solved := s.SolveLogically(false); solved {
+		t.Fatal("The puzzle wasn't supposed to be solved without guessing")
+	}
+
+	s = initRows(hardPuzzle)
+	solution := initRows(hardPuzzle)
+	solution.Solve()
+
+	steps, solved := s.SolveLogically(true)
+
+	if !solved || !s.IsEqual(solution) {
+		t.Fatal("Unable to solve sudoku with guessing")
+	}
+
+	guessed := false
+
+	for _, step := range steps {
+		guessed = guessed || step.Technique == sudoku.Guess
+	}
+
+	if !guessed {
+		t.Error("The solver was expected to guess")
+	}
+}
+
+func TestGrade(t *testing.T) {
+	rating, err := initSudoku().Grade()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rating.Difficulty == sudoku.Extreme || rating.Techniques[sudoku.HiddenSingle] == 0 {
+		t.Errorf("The puzzle was graded as %s", rating)
+	}
+
+	rating, err = initRows(hardPuzzle).Grade()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rating.Difficulty != sudoku.Extreme || rating.Techniques[sudoku.Guess] == 0 {
+		t.Errorf("The hard puzzle was graded as %s", rating)
+	}
+
+	broken := initSudoku()
+	broken.SetCell(0, 3, 7)
+
+	if _, err := broken.Grade(); err == nil {
+		t.Error("A puzzle without a solution was graded")
+	}
+}
+
+func TestParseDifficulty(t *testing.T) {
+	for _, d := range []sudoku.Difficulty{sudoku.Easy, sudoku.Medium, sudoku.Hard, sudoku.Expert, sudoku.Extreme} {
+		if parsed, err := sudoku.ParseDifficulty(strings.ToLower(d.String())); err != nil || parsed != d {
+			t.Errorf("Unable to parse %s", d)
+		}
+	}
+
+	if _, err := sudoku.ParseDifficulty("impossible"); err == nil {
+		t.Error("An unknown difficulty was parsed")
+	}
+}
@@ -1,285 +1,428 @@
 package sudoku
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/rand"
 	"os"
-	"strconv"
+	"strings"
+	"unicode"
 )
 
+// MaxN is the biggest board size that can be represented with the available symbols.
+const MaxN = 25
+
+// symbols holds the characters that represent the numbers of a board, in order. Boards up to
+// 9x9 only use digits, while the bigger ones continue with letters (e.g. 1-9 and A-G for 16x16).
+const symbols = "123456789ABCDEFGHIJKLMNOP"
+
 // Sudoku defines the structure of the entire Sudoku board.
 type Sudoku struct {
-	// Figure out the logic here. Ideally we want them to add
-	// as many as they want, but currently the logic below is
-	// just for 9 (the typical).
-	N     uint8  `json:"n"` // The number of columns and rows.
-	Seed  int64  `json:"seed"`
-	Board []*Box `json:"board"`
+	N         uint8  `json:"n"`          // The number of columns and rows; 9 if left empty.
+	BoxWidth  uint8  `json:"box_width"`  // The number of columns of each box; derived from N if left empty.
+	BoxHeight uint8  `json:"box_height"` // The number of rows of each box; derived from N if left empty.
+	Seed      int64  `json:"seed"`
+	Board     []*Box `json:"board"`
+
+	// Rating holds the rating of the puzzle, which is saved along with the board. It's
+	// only set by the caller, usually from `Grade`.
+	Rating *Rating `json:"rating,omitempty"`
+
+	// Symmetry is the pattern the clues of the generated puzzles follow. `SymmetryMask` holds
+	// the groups of cells of a `CustomSymmetry` (see `ValidateSymmetryMask`).
+	Symmetry     Symmetry `json:"symmetry,omitempty"`
+	SymmetryMask string   `json:"symmetry_mask,omitempty"`
+
+	// Solver is the algorithm that solves the board. `Constraints` holds the extra groups of
+	// cells of a variant, which switch the solver to `DLXSolver`, since it's the only one that
+	// supports them. The generator doesn't take them into account.
+	Solver      Solver       `json:"-"`
+	Constraints []Constraint `json:"constraints,omitempty"`
+
+	// Eliminations holds the candidates that were ruled out by hand and Notes the pencil marks
+	// of the player, cell by cell and row by row. Both are nil until they're used.
+	Eliminations []CandidateSet `json:"eliminations,omitempty"`
+	Notes        []CandidateSet `json:"notes,omitempty"`
+
 	count int64
+	stats Stats
 	rand  *rand.Rand
 }
 
-// Init initializes the Sudoku instance. It's required before running `Fill`.
+// Stats holds the work that went into generating a board, from `Fill` and `GeneratePuzzle` to
+// the generators which call them (e.g. `GenerateWithOptions`). It's reset by `Init`.
+type Stats struct {
+	Fills      int64 // The times the board was filled from scratch, including the restarts.
+	Restarts   int64 // The fills that ran into a dead end and started over.
+	Backtracks int64 // The numbers that were taken back while filling.
+	Puzzles    int64 // The puzzles that were generated, including the ones that were thrown away.
+	Removals   int64 // The clues, or the groups of them, whose removal was tried.
+}
+
+// Init initializes the Sudoku instance. It's required before running `Fill`. The size of
+// the board is taken from `N` and the size of its boxes from `BoxWidth` and `BoxHeight`. If
+// the latter are left empty, perfect squares get square boxes (e.g. 3x3 for 9) and the rest
+// get the widest boxes that have at least 2 rows (e.g. 3x2 for 6 and 4x3 for 12).
 func (s *Sudoku) Init() {
-	s.N = 9
+	if s.N == 0 {
+		s.N = 9
+	}
+
+	if s.BoxWidth == 0 || s.BoxHeight == 0 {
+		s.BoxWidth, s.BoxHeight = boxDimensions(s.N)
+	}
+
 	s.count = 0
+	s.stats = Stats{}
 	s.Board = make([]*Box, s.N)
+	s.Eliminations = nil
+	s.Notes = nil
+
+	// The boxes and their numbers are allocated all at once.
+	n := int(s.N)
+	boxes := make([]Box, n)
+	numbers := make([]uint8, n*n)
 
 	for i := range s.Board {
-		s.Board[i] = &Box{N: s.N}
+		boxes[i] = Box{N: s.N, Width: s.BoxWidth, Height: s.BoxHeight, numbers: numbers[i*n : (i+1)*n : (i+1)*n]}
+		s.Board[i] = &boxes[i]
 		s.Board[i].Init()
 	}
 
 	s.rand = rand.New(rand.NewSource(s.Seed))
 }
 
+// reseed starts the random numbers of the board over from a seed. The generator is reused,
+// since creating one takes a few kilobytes and the generation reseeds it on every retry.
+func (s *Sudoku) reseed(seed int64) {
+	if s.rand == nil {
+		s.rand = rand.New(rand.NewSource(seed))
+	} else {
+		s.rand.Seed(seed)
+	}
+}
+
 // Fill fills the Sudoku board with numbers.
 func (s *Sudoku) Fill() {
-	for i := 0; i <= 8; i++ {
-		box := s.Board[i]
-
-		// Empty a box before it's processed.
-		s.Board[i].Empty()
-
-		var boxH1 *Box
-		var boxH2 *Box
-		var boxV1 *Box
-		var boxV2 *Box
-
-		// Get the adjacent vertical boxes.
-		if i < 3 {
-			boxV1 = s.Board[i+3]
-			boxV2 = s.Board[i+6]
-		} else if i < 6 {
-			boxV1 = s.Board[i-3]
-			boxV2 = s.Board[i+3]
-		} else {
-			boxV1 = s.Board[i-3]
-			boxV2 = s.Board[i-6]
+	s.fillBoard(nil)
+}
+
+// FillContext is `Fill`, but it stops with an `AbortError` once the context is done or its
+// budget runs out (see `WithBudget`), in which case the board is left empty.
+func (s *Sudoku) FillContext(ctx context.Context) error {
+	return s.fillBoard(newLimiter(ctx))
+}
+
+// fillBoard fills the board, until the limiter stops it.
+func (s *Sudoku) fillBoard(l *limiter) error {
+	for _, box := range s.Board {
+		box.Empty()
+	}
+
+	// The board is filled box by box, by picking a random number out of the ones that can go
+	// in each cell. If the possible choices have been exahusted, we go back to the previous
+	// cells and try different numbers. This back-tracking could take a very long time, so the
+	// circuit-breaker will reset the entire board and start again, without resetting the seed
+	// that was used. Boards up to 9x9 are quick to fill from scratch, so they're reset on the
+	// first dead end, while the bigger ones practically never fill without back-tracking.
+	//
+	// A fill which gives up takes back every number it placed, so the same grid is used for
+	// every restart.
+	g := newGrid(s)
+	g.limit = l
+	defer g.release()
+
+	for {
+		budget := 0
+
+		if s.N > 9 {
+			budget = int(s.N) * int(s.N) * int(s.N)
 		}
 
-		// Get the adjacent horizontal boxes.
-		if i%3 == 0 {
-			boxH1 = s.Board[i+1]
-			boxH2 = s.Board[i+2]
-		} else if i%3 == 1 {
-			boxH1 = s.Board[i-1]
-			boxH2 = s.Board[i+1]
-		} else {
-			boxH1 = s.Board[i-2]
-			boxH2 = s.Board[i-1]
-		}
-
-		for j := 0; j < 9; j++ {
-			row := make([]uint8, 9)
-
-			// Get all the numbes in the current row.
-			if j < 3 {
-				copy(row, boxH1.GetRow(0))
-				row = append(row, boxH2.GetRow(0)...)
-			} else if j < 6 {
-				copy(row, boxH1.GetRow(1))
-				row = append(row, boxH2.GetRow(1)...)
-			} else if j < 9 {
-				copy(row, boxH1.GetRow(2))
-				row = append(row, boxH2.GetRow(2)...)
-			}
+		s.stats.Fills++
 
-			// Get all the numbers in the current column.
-			col := append(boxV1.GetCol(j%3), boxV2.GetCol(j%3)...)
+		if s.fill(g, 0, &budget) {
+			break
+		}
 
-			possible := make([]uint8, 0)
+		if err := l.check(); err != nil {
+			return err
+		}
 
-			// Get the list of all of the numbers that can possibly apply to
-			// the current cell.
-			for k := uint8(1); k <= 9; k++ {
-				isPossible := true
+		s.stats.Restarts++
+	}
 
-				if box.Has(k) {
-					isPossible = false
-				} else {
-					for _, v := range row {
-						if v == k {
-							isPossible = false
-							break
-						}
-					}
-
-					for _, v := range col {
-						if v == k {
-							isPossible = false
-							break
-						}
-					}
-				}
+	g.write(s)
 
-				if isPossible {
-					possible = append(possible, k)
-				}
-			}
+	return nil
+}
 
-			// If the possible choices have been exahusted, we go to the previous box
-			// and set it up from scratch. There is a possibility that this back-tracking
-			// could lead to an infinite loop, but the circuit-breaker will prevent this
-			// by resetting the entire board and starting again, without resetting the
-			// seed that was used.
-			if len(possible) == 0 {
-				for _, box := range s.Board {
-					box.Empty()
-				}
+// fill places a random number in the cell with the index `idx` (counting box by box) and
+// recursively fills the rest of the grid. It gives up once it has back-tracked more times
+// than the `budget` allows.
+func (s *Sudoku) fill(g *grid, idx int, budget *int) bool {
+	n := int(s.N)
 
-				i = 0
-				s.Fill()
-				return
-			}
+	if idx == n*n {
+		return true
+	}
 
-			// Get a random number from all the possibilities and insert it in the target
-			// position of the current box.
-			num := possible[s.rand.Intn(len(possible))]
-			box.InsertPos(j, num)
+	// Going through the cells in order would leave the bigger boards stuck in dead ends for
+	// too long, so they fill the most constrained cell first.
+	var cell int
+	var possibilities uint32
+
+	if s.N > 9 {
+		cell, possibilities = g.mostConstrained()
+	} else {
+		cell = g.cellOf[idx]
+		possibilities = g.possibilities(cell)
+	}
+
+	var buffer [MaxN]uint8
+	possible := buffer[:0]
+
+	for num := uint8(1); int(num) <= n; num++ {
+		if possibilities&(1<<num) != 0 {
+			possible = append(possible, num)
+		}
+	}
+
+	for len(possible) > 0 {
+		// Get a random number from all the possibilities and insert it in the target cell.
+		k := s.rand.Intn(len(possible))
+		g.set(cell, possible[k])
+		g.limit.visit()
+
+		if !g.exhausted() && s.fill(g, idx+1, budget) {
+			return true
+		}
+
+		g.unset(cell)
+		s.stats.Backtracks++
+
+		if *budget <= 0 || g.exhausted() {
+			return false
 		}
+
+		*budget--
+		possible = append(possible[:k], possible[k+1:]...)
 	}
+
+	return false
 }
 
 // GeneratePuzzle needs to run after `Fill`. It generates a proper puzzle with some
 // indecies which are hidden.
 // TODO: Start from scratch.
 func (s *Sudoku) GeneratePuzzle() *Sudoku {
-	const maxEmptyPerBox = 8
-	const minEmptyPerBox = 4
+	puzzle, _ := s.generatePuzzle(nil)
 
-	s.rand = rand.New(rand.NewSource(s.Seed + s.count))
+	return puzzle
+}
 
-	// This will hold the raw values of our board.
-	board := make([][]uint8, 9)
+// GeneratePuzzleContext is `GeneratePuzzle`, but it stops with an `AbortError` once the context
+// is done or its budget runs out (see `WithBudget`).
+func (s *Sudoku) GeneratePuzzleContext(ctx context.Context) (*Sudoku, error) {
+	return s.generatePuzzle(newLimiter(ctx))
+}
 
-	// Get all the numbers of our board into the array.
-	for i, box := range s.Board {
-		board[i] = box.GetNumbers()
-	}
+// maxPuzzleRetries is the number of puzzles `GeneratePuzzle` throws away for having more than one
+// solution before it falls back to `carve`, which always leaves a single one. Hardly any board
+// needs more than a few dozen of them.
+const maxPuzzleRetries = 1000
 
-	// This variable will count the number of cells we empty out.
-	totalRemoved := 0
+// generatePuzzle generates a puzzle out of the board, until the limiter stops it.
+func (s *Sudoku) generatePuzzle(l *limiter) (*Sudoku, error) {
+	n := int(s.N)
 
-	// In order for a puzzle to be valid, it needs to to have all numbers present, otherwise
-	// it's likely a puzzle will be unsolvable.
-	numMap := make(map[uint8]int)
-	numMap[1] = 9
-	numMap[2] = 9
-	numMap[3] = 9
-	numMap[4] = 9
-	numMap[5] = 9
-	numMap[6] = 9
-	numMap[7] = 9
-	numMap[8] = 9
-	numMap[9] = 9
+	// For the typical 9x9 board each box gets 4 to 8 of its cells emptied.
+	maxEmptyPerBox := n * 8 / 9
+	minEmptyPerBox := n * 4 / 9
 
-	for i, box := range s.Board {
-		board[i] = box.GetNumbers()
+	// The bigger boards have too many small groups of cells whose numbers can be swapped around
+	// for the random removal below to leave a single solution, so they empty one pair of cells
+	// at a time instead. The same goes for the symmetries other than the rotational one, which
+	// the removal below is built around.
+	if n > 9 || s.Symmetry != RotationalSymmetry {
+		s.reseed(s.Seed + s.count)
+
+		return s.carvePuzzle(l)
 	}
 
-	for i := 0; i < 4; i++ {
-		opposite := 8 - i
+	// This will hold the raw values of our board, and the puzzle the ones that are left. Both
+	// are reused by every retry, and the numbers of the board are kept on the stack.
+	var rows [MaxN][]uint8
+	var cells [MaxN * MaxN]uint8
+	var numMap [MaxN + 1]int
+	board := rows[:n]
+	puzzle := &Sudoku{
+		N:         s.N,
+		BoxWidth:  s.BoxWidth,
+		BoxHeight: s.BoxHeight,
+		Seed:      s.Seed,
+		Symmetry:  s.Symmetry,
+		Solver:    s.Solver,
+	}
+	puzzle.Init()
 
-		amountToEmpty := s.rand.Intn(maxEmptyPerBox-minEmptyPerBox) + minEmptyPerBox
+	for i := range board {
+		board[i] = cells[i*n : (i+1)*n]
+	}
 
-		for j := amountToEmpty; j > 0; {
-			index := s.rand.Intn(9)
+	// A puzzle that's left with more than one solution is thrown away, and the next one is
+	// generated with the next seed (through the counter).
+	for retries := 0; ; retries++ {
+		s.reseed(s.Seed + s.count)
 
-			if board[i][index] == 0 {
-				continue
-			}
+		if retries == maxPuzzleRetries {
+			return s.carvePuzzle(l)
+		}
 
-			available := numMap[board[i][index]]
-			oppositeIndex := 8 - index
-			oppositeAvailable := numMap[board[opposite][oppositeIndex]]
+		s.stats.Puzzles++
 
-			if (board[opposite][oppositeIndex] == board[i][index] &&
-				available < 2) ||
-				oppositeAvailable < 2 {
-				continue
-			}
+		// Get all the numbers of our board into the array.
+		for i, box := range s.Board {
+			copy(board[i], box.numbers)
+		}
 
-			totalRemoved += 2
+		// This variable will count the number of cells we empty out.
+		totalRemoved := 0
 
-			if board[opposite][oppositeIndex] == board[i][index] {
-				available -= 1
-			} else {
-				numMap[board[opposite][oppositeIndex]] = oppositeAvailable - 1
-			}
+		// In order for a puzzle to be valid, it needs to to have all numbers present, otherwise
+		// it's likely a puzzle will be unsolvable.
+		for k := 1; k <= n; k++ {
+			numMap[k] = n
+		}
 
-			numMap[board[i][index]] = available - 1
+		// Each box in the first half of the board is emptied along with the opposite one, which
+		// keeps the puzzle symmetrical.
+		for i := 0; i < n/2; i++ {
+			opposite := n - 1 - i
 
-			board[i][index] = 0
-			board[opposite][oppositeIndex] = 0
+			amountToEmpty := s.rand.Intn(maxEmptyPerBox-minEmptyPerBox) + minEmptyPerBox
 
-			j--
-		}
-	}
+			// The attempts are capped, since on the bigger boards it's possible to run out of
+			// cells that can be emptied.
+			for j, attempts := amountToEmpty, 0; j > 0 && attempts < n*n; attempts++ {
+				index := s.rand.Intn(n)
 
-	// For the fifth box (the center one).
-	for j := 0; j < 4; j++ {
-		shouldEmpty := s.rand.Intn(4) >= 1
+				if board[i][index] == 0 {
+					continue
+				}
 
-		if !shouldEmpty {
-			continue
+				s.stats.Removals++
+
+				available := numMap[board[i][index]]
+				oppositeIndex := n - 1 - index
+				oppositeAvailable := numMap[board[opposite][oppositeIndex]]
+
+				if (board[opposite][oppositeIndex] == board[i][index] &&
+					available < 2) ||
+					oppositeAvailable < 2 {
+					continue
+				}
+
+				totalRemoved += 2
+
+				if board[opposite][oppositeIndex] == board[i][index] {
+					available -= 1
+				} else {
+					numMap[board[opposite][oppositeIndex]] = oppositeAvailable - 1
+				}
+
+				numMap[board[i][index]] = available - 1
+
+				board[i][index] = 0
+				board[opposite][oppositeIndex] = 0
+
+				j--
+			}
 		}
 
-		available := numMap[board[4][j]]
-		oppositeIndex := 8 - j
-		oppositeAvailable := numMap[board[4][oppositeIndex]]
+		// Boards with an odd number of boxes also have a center one, which is its own opposite.
+		if n%2 == 1 {
+			center := n / 2
 
-		if (board[4][oppositeIndex] == board[4][j] &&
-			available < 2) ||
-			oppositeAvailable < 2 {
-			continue
+			for j := 0; j < n/2; j++ {
+				shouldEmpty := s.rand.Intn(4) >= 1
+
+				if !shouldEmpty {
+					continue
+				}
+
+				s.stats.Removals++
+
+				available := numMap[board[center][j]]
+				oppositeIndex := n - 1 - j
+				oppositeAvailable := numMap[board[center][oppositeIndex]]
+
+				if (board[center][oppositeIndex] == board[center][j] &&
+					available < 2) ||
+					oppositeAvailable < 2 {
+					continue
+				}
+
+				totalRemoved += 2
+
+				if board[center][oppositeIndex] == board[center][j] {
+					available -= 1
+				} else {
+					numMap[board[center][oppositeIndex]] = oppositeAvailable - 1
+				}
+
+				numMap[board[center][j]] = available - 1
+
+				board[center][j] = 0
+				board[center][oppositeIndex] = 0
+			}
 		}
 
-		if j == 4 {
-			totalRemoved++
-		} else {
-			totalRemoved += 2
+		for i := 0; i < n; i++ {
+			puzzle.Board[i].SetNumbers(board[i])
 		}
 
-		if board[4][oppositeIndex] == board[4][j] {
-			available -= 1
-		} else {
-			numMap[board[4][oppositeIndex]] = oppositeAvailable - 1
+		multiple := puzzle.countSolutions(2, l) > 1
+
+		if err := l.check(); err != nil {
+			return nil, err
 		}
 
-		numMap[board[4][j]] = available - 1
+		if !multiple {
+			break
+		}
 
-		board[4][j] = 0
-		board[4][oppositeIndex] = 0
+		s.count++
 	}
 
-	puzzle := &Sudoku{
-		N:    s.N,
-		Seed: s.Seed,
-	}
-	puzzle.Init()
+	// Harden the puzzle.
+	err := puzzle.hardenPuzzle(l)
+	s.stats.Removals += puzzle.stats.Removals
 
-	for i := 0; i < 9; i++ {
-		box := &Box{N: s.N}
-		box.Init()
-		box.SetNumbers(board[i])
-		puzzle.Board[i] = box
+	if err != nil {
+		return nil, err
 	}
 
-	solvedPuzzle := &Sudoku{}
-	solvedPuzzle.Copy(puzzle)
+	return puzzle, nil
+}
 
-	if puzzle.HasMultipleSolutions() {
-		s.count++
-		return s.GeneratePuzzle()
-	}
+// carvePuzzle generates a puzzle out of the board with `carve`, until the limiter stops it.
+func (s *Sudoku) carvePuzzle(l *limiter) (*Sudoku, error) {
+	s.stats.Puzzles++
 
-	// Harden the puzzle.
-	puzzle.Harden()
+	puzzle := &Sudoku{}
+	puzzle.Copy(s)
+	err := puzzle.carve(s.rand, s.orbits(), l)
+	s.stats.Removals += puzzle.stats.Removals
 
-	return puzzle
+	if err != nil {
+		return nil, err
+	}
+
+	return puzzle, nil
 }
 
 // Harden itterates over the existing puzzle (after calling `GeneratePuzzle`), or board (after
@@ -288,21 +431,41 @@ func (s *Sudoku) GeneratePuzzle() *Sudoku {
 // backtracking, similar to `Solve`, but it empties cells until it's reached a difficulty that
 // we want.
 func (s *Sudoku) Harden() {
-	s.rand = rand.New(rand.NewSource(int64(s.N + 1)))
+	s.hardenPuzzle(nil)
+}
+
+// HardenContext is `Harden`, but it stops with an `AbortError` once the context is done or its
+// budget runs out (see `WithBudget`), in which case the puzzle is left with a single solution,
+// just not as hard as it could be.
+func (s *Sudoku) HardenContext(ctx context.Context) error {
+	return s.hardenPuzzle(newLimiter(ctx))
+}
 
-	prevNonEmpty := 45
+// hardenPuzzle hardens the puzzle, until the limiter stops it.
+func (s *Sudoku) hardenPuzzle(l *limiter) error {
+	s.reseed(int64(s.N + 1))
+
+	// The thresholds were picked for the 9x9 board, so they are scaled for the rest.
+	cells := int(s.N) * int(s.N)
+	half := cells * 45 / 81
+	minNonEmpty := cells * 15 / 81
+
+	prevNonEmpty := half
 	count := 0
+	orbits := s.orbits()
 
 	for {
 		// Harden the puzzle.
-		s.harden(s.rand.Int63())
+		if err := s.harden(s.rand.Int63(), orbits, l); err != nil {
+			return err
+		}
 
-		nonEmpty := 45 - s.CountEmpty()
+		nonEmpty := half - s.CountEmpty()
 
 		// We want the function to exit if the non empty cells are less or equal to 15, or
 		// if we've iterated over the board 5 times and it could not be hardened any more.
-		if nonEmpty <= 15 || (prevNonEmpty == nonEmpty && count > 5) {
-			return
+		if nonEmpty <= minNonEmpty || (prevNonEmpty == nonEmpty && count > 5) {
+			return nil
 		}
 
 		prevNonEmpty = nonEmpty
@@ -315,39 +478,228 @@ func (s *Sudoku) Harden() {
 	}
 }
 
-func (s *Sudoku) harden(count int64) {
-	s.rand = rand.New(rand.NewSource(s.Seed + count))
+// harden empties random cells, along with the rest of their orbit (see `orbits`), as long as
+// the puzzle is left with a single solution. Every time it empties an orbit, it starts over
+// from the first box with the next seed, until it goes through all of them without emptying
+// any. If the limiter stops it, the cells of the last orbit are filled back in.
+func (s *Sudoku) harden(count int64, orbits [][]int, l *limiter) error {
+	n := int(s.N)
+	orbitOf := make([]int, n*n)
+	backup := make([]uint8, 0, n*n)
+
+	for i, orbit := range orbits {
+		for _, idx := range orbit {
+			orbitOf[idx] = i
+		}
+	}
+
+	// With the rotational symmetry, the orbits of the first half of the boxes cover the rest.
+	boxes := n
+
+	if s.Symmetry == RotationalSymmetry {
+		boxes = (n + 1) / 2
+	}
+
+	for emptied := true; emptied; count++ {
+		emptied = false
+		s.reseed(s.Seed + count)
+
+	scan:
+		for i := 0; i < boxes; i++ {
+			for j, num := range s.Board[i].numbers {
+				if num == 0 {
+					continue
+				}
+
+				shouldEmpty := s.rand.Intn(2) == 1
+
+				if !shouldEmpty {
+					continue
+				}
+
+				s.stats.Removals++
+
+				row, col := s.rowColFromBoxPos(i, j)
+				orbit := orbits[orbitOf[row*n+col]]
+				backup = backup[:len(orbit)]
+
+				for k, idx := range orbit {
+					backup[k] = s.GetCell(idx/n, idx%n)
+					s.SetCell(idx/n, idx%n, 0)
+				}
+
+				multiple := s.countSolutions(2, l) > 1
+				err := l.check()
+
+				if !multiple && err == nil {
+					emptied = true
+					break scan
+				}
+
+				for k, idx := range orbit {
+					s.SetCell(idx/n, idx%n, backup[k])
+				}
+
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// carveMaxNodes is the number of nodes each uniqueness check of `carve` is allowed to visit.
+const carveMaxNodes = 10000
 
-	for i := 0; i < 5; i++ {
-		box := s.Board[i]
+// carve goes over the orbits of the board (see `orbits`) in a random order and empties all the
+// cells of each one, as long as the puzzle is left with a single solution. The board is left
+// alone if the limiter stops it.
+func (s *Sudoku) carve(r *rand.Rand, orbits [][]int, l *limiter) error {
+	g := newGrid(s)
+	g.maxNodes = carveMaxNodes
+	g.limit = l
+	defer g.release()
 
-		for j, num := range box.GetNumbers() {
-			if num == 0 {
+	for _, i := range r.Perm(len(orbits)) {
+		orbit := orbits[i]
+		nums := make([]uint8, len(orbit))
+		s.stats.Removals++
+
+		for k, idx := range orbit {
+			nums[k] = g.cells[idx]
+
+			if nums[k] != 0 {
+				g.unset(idx)
+			}
+		}
+
+		// The puzzle had a single solution before emptying the cells, so any other one would
+		// need a different number in at least one of them. Checking each cell in turn, with
+		// the ones before it filled back in, covers all of the other solutions.
+		// A few of these searches could take very long, so the cells are left alone if they
+		// run out of nodes.
+		g.nodes = 0
+		unique := true
+
+		for k, idx := range orbit {
+			if nums[k] == 0 {
 				continue
 			}
 
-			shouldEmpty := s.rand.Intn(2) == 1
+			if unique {
+				unique = !g.hasOtherSolution(idx, nums[k])
+			}
 
-			if shouldEmpty {
-				oppositeIndex := 8 - j
-				oppositeBox := s.Board[8-i]
+			g.set(idx, nums[k])
+		}
 
-				backup := num
-				oppositeBackup := oppositeBox.GetPos(oppositeIndex)
+		if err := l.check(); err != nil {
+			return err
+		}
 
-				box.InsertPos(j, 0)
-				oppositeBox.InsertPos(oppositeIndex, 0)
+		unique = unique && !g.exhausted()
 
-				if !s.HasMultipleSolutions() {
-					s.harden(count + 1)
-					return
+		if unique {
+			for k, idx := range orbit {
+				if nums[k] != 0 {
+					g.unset(idx)
 				}
-
-				box.InsertPos(j, backup)
-				oppositeBox.InsertPos(oppositeIndex, oppositeBackup)
 			}
 		}
 	}
+
+	g.write(s)
+
+	return nil
+}
+
+// ErrNotUnique is returned when a puzzle needs a single solution and doesn't have one.
+var ErrNotUnique = errors.New("the puzzle doesn't have a single solution")
+
+// Minimize empties clues of the puzzle, one at a time and in a random order, as long as it's
+// left with a single solution. Unlike `Harden`, it doesn't stop until every clue is needed, so
+// the puzzle ends up minimal, but it ignores the symmetry to get there. It returns
+// `ErrNotUnique` if the puzzle didn't have a single solution to begin with.
+func (s *Sudoku) Minimize() error {
+	return s.minimize(nil)
+}
+
+// MinimizeContext is `Minimize`, but it stops with an `AbortError` once the context is done or
+// its budget runs out (see `WithBudget`), in which case the puzzle is left as it was.
+func (s *Sudoku) MinimizeContext(ctx context.Context) error {
+	return s.minimize(newLimiter(ctx))
+}
+
+// minimize empties every clue of the puzzle that isn't needed, until the limiter stops it.
+func (s *Sudoku) minimize(l *limiter) error {
+	g := newGrid(s)
+	g.limit = l
+	defer g.release()
+	count := g.count(2)
+
+	if err := l.check(); err != nil {
+		return err
+	}
+
+	if count != 1 {
+		return ErrNotUnique
+	}
+
+	// Emptying a clue can only add solutions, so a clue which was needed at some point is
+	// still needed at the end and a single pass is enough.
+	for _, idx := range rand.New(rand.NewSource(s.Seed)).Perm(len(g.cells)) {
+		num := g.cells[idx]
+
+		if num == 0 {
+			continue
+		}
+
+		g.unset(idx)
+
+		if g.hasOtherSolution(idx, num) {
+			g.set(idx, num)
+		}
+
+		if err := l.check(); err != nil {
+			return err
+		}
+	}
+
+	g.write(s)
+
+	return nil
+}
+
+// IsMinimal checks whether every clue of the puzzle is needed for it to have a single solution.
+// It also returns the clues which aren't, each of which could be emptied on its own. It returns
+// `ErrNotUnique` if the puzzle doesn't have a single solution.
+func (s *Sudoku) IsMinimal() (bool, []Cell, error) {
+	g := newGrid(s)
+	defer g.release()
+
+	if g.count(2) != 1 {
+		return false, nil, ErrNotUnique
+	}
+
+	redundant := make([]Cell, 0)
+
+	for idx, num := range g.cells {
+		if num == 0 {
+			continue
+		}
+
+		g.unset(idx)
+
+		if !g.hasOtherSolution(idx, num) {
+			redundant = append(redundant, Cell{Row: idx / g.n, Col: idx % g.n})
+		}
+
+		g.set(idx, num)
+	}
+
+	return len(redundant) == 0, redundant, nil
 }
 
 // IsEqual checks whether two Sudoku boards are equal.
@@ -371,19 +723,21 @@ func (s *Sudoku) GetCounter() int64 {
 	return s.count
 }
 
+// GetStats returns the work that went into generating the board, on top of the iterations of
+// `GetCounter` (see `Stats`).
+func (s *Sudoku) GetStats() Stats {
+	return s.stats
+}
+
 // GetRow returns all the numbers in a specific row.
 func (s *Sudoku) GetRow(row int) []uint8 {
-	numbers := make([]uint8, 0)
-	boxIdx := 2
-
-	if row < 3 {
-		boxIdx = 0
-	} else if row < 6 {
-		boxIdx = 1
-	}
+	numbers := make([]uint8, 0, s.N)
+	w, h := s.BoxWidth, s.BoxHeight
+	boxesPerRow := int(s.N / w)
+	boxIdx := row / int(h)
 
-	for i := boxIdx * 3; i < boxIdx*3+3; i++ {
-		numbers = append(numbers, s.Board[i].GetRow(row%3)...)
+	for i := boxIdx * boxesPerRow; i < boxIdx*boxesPerRow+boxesPerRow; i++ {
+		numbers = append(numbers, s.Board[i].GetRow(row%int(h))...)
 	}
 
 	return numbers
@@ -391,17 +745,14 @@ func (s *Sudoku) GetRow(row int) []uint8 {
 
 // GetCol gets all the numbers in a given column.
 func (s *Sudoku) GetCol(col int) []uint8 {
-	numbers := make([]uint8, 0)
-	boxIdx := 2
+	numbers := make([]uint8, 0, s.N)
+	w, h := int(s.BoxWidth), int(s.BoxHeight)
+	boxesPerRow := int(s.N) / w
 
-	if col < 3 {
-		boxIdx = 0
-	} else if col < 6 {
-		boxIdx = 1
-	}
-
-	for i := 0 + boxIdx; i <= 6+boxIdx; i += 3 {
-		numbers = append(numbers, s.Board[i].GetCol(col%3)...)
+	for i := col / w; i < int(s.N); i += boxesPerRow {
+		for r := 0; r < h; r++ {
+			numbers = append(numbers, s.Board[i].numbers[r*w+col%w])
+		}
 	}
 
 	return numbers
@@ -409,53 +760,86 @@ func (s *Sudoku) GetCol(col int) []uint8 {
 
 // GetBoxFromRowCol returns the box from a specific point in the board.
 func (s *Sudoku) GetBoxFromRowCol(row int, col int) *Box {
-	boxRow := 2
-	boxCol := 2
+	boxIdx, _ := s.boxPosFromRowCol(row, col)
 
-	if row < 3 {
-		boxRow = 0
-	} else if row < 6 {
-		boxRow = 1
-	}
+	return s.Board[boxIdx]
+}
+
+// GetCell returns the number in a specific row and column, or 0 if the cell is empty.
+func (s *Sudoku) GetCell(row, col int) uint8 {
+	boxIdx, pos := s.boxPosFromRowCol(row, col)
 
-	if col < 3 {
-		boxCol = 0
-	} else if col < 6 {
-		boxCol = 1
+	return s.Board[boxIdx].GetPos(pos)
+}
+
+// SetCell places a number in a specific row and column. A 0 empties the cell.
+func (s *Sudoku) SetCell(row, col int, n uint8) bool {
+	if row < 0 || col < 0 || row >= int(s.N) || col >= int(s.N) {
+		return false
 	}
 
-	return s.Board[(boxRow*3)+boxCol]
+	boxIdx, pos := s.boxPosFromRowCol(row, col)
+
+	return s.Board[boxIdx].InsertPos(pos, n)
+}
+
+// boxPosFromRowCol converts a row and a column to the index of the box and the position of
+// the cell within that box.
+func (s *Sudoku) boxPosFromRowCol(row, col int) (int, int) {
+	w, h := s.BoxWidth, s.BoxHeight
+	boxesPerRow := int(s.N / w)
+	boxIdx := (row/int(h))*boxesPerRow + col/int(w)
+	pos := (row%int(h))*int(w) + col%int(w)
+
+	return boxIdx, pos
+}
+
+// rowColFromBoxPos converts the index of a box and the position of a cell within it to a
+// row and a column.
+func (s *Sudoku) rowColFromBoxPos(boxIdx, pos int) (int, int) {
+	w, h := s.BoxWidth, s.BoxHeight
+	boxesPerRow := int(s.N / w)
+	row := (boxIdx/boxesPerRow)*int(h) + pos/int(w)
+	col := (boxIdx%boxesPerRow)*int(w) + pos%int(w)
+
+	return row, col
 }
 
 // Solve tries to solve the puzzle and returns the first possible solution.
 func (s *Sudoku) Solve() bool {
-	for i := 0; i < 9; i++ {
-		row := s.GetRow(i)
+	solved, _ := s.solve(nil)
 
-		for j, num := range row {
-			if num != 0 {
-				continue
-			}
+	return solved
+}
 
-			column := s.GetCol(j)
-			box := s.GetBoxFromRowCol(i, j)
-			possibilites := getVHPossibilities(row, column, box)
+// SolveContext is `Solve`, but it stops with an `AbortError` once the context is done or its
+// budget runs out (see `WithBudget`), in which case the board is left as it was.
+func (s *Sudoku) SolveContext(ctx context.Context) (bool, error) {
+	return s.solve(newLimiter(ctx))
+}
 
-			for _, possibility := range possibilites {
-				box.Insert(uint8(j%3), uint8(i%3), possibility)
+// solve solves the board, until the limiter stops it.
+func (s *Sudoku) solve(l *limiter) (bool, error) {
+	var solved bool
 
-				if s.Solve() {
-					return true
-				} else {
-					box.Insert(uint8(j%3), uint8(i%3), 0)
-				}
-			}
+	if s.usesDLX() {
+		solved = s.solveDLX(l)
+	} else {
+		g := newGrid(s)
+		g.limit = l
+		solved = g.search(func() bool {
+			g.write(s)
+
+			return true
+		})
+		g.release()
+	}
 
-			return false
-		}
+	if l.stopped() {
+		return false, l.err
 	}
 
-	return true
+	return solved, nil
 }
 
 // CountEmpty returns the total number of empty cells in the puzzle.
@@ -471,73 +855,168 @@ func (s *Sudoku) CountEmpty() int {
 
 // CountSolutions returns the total amount of solutions for this board.
 func (s *Sudoku) CountSolutions() int64 {
-	sudoku := &Sudoku{}
-	sudoku.Copy(s)
+	return s.countSolutions(0, nil)
+}
 
-	return s.internalCount(sudoku, 0, int64(sudoku.CountEmpty()), false)
+// CountSolutionsContext is `CountSolutions`, but it stops with an `AbortError` once the context
+// is done or its budget runs out (see `WithBudget`), along with the solutions it counted so far.
+func (s *Sudoku) CountSolutionsContext(ctx context.Context) (int64, error) {
+	l := newLimiter(ctx)
+	count := s.countSolutions(0, l)
+
+	if l.stopped() {
+		return count, l.err
+	}
+
+	return count, nil
 }
 
-// HasMultipleSolutions returns true if there are multiple solutions, or false if there
-// is only one.
-func (s *Sudoku) HasMultipleSolutions() bool {
-	sudoku := &Sudoku{}
-	sudoku.Copy(s)
+// CountSolutionsUpTo counts the solutions of the board like `CountSolutions`, but it stops once
+// it has found `limit` of them, so that it's quick even on boards with too many solutions to
+// count. It also returns whether it stopped at the limit, in which case there may be more. A
+// limit of 0 counts all of them.
+func (s *Sudoku) CountSolutionsUpTo(limit int64) (int64, bool) {
+	count, _ := s.findSolutions(limit, false, nil)
 
-	return s.internalCount(sudoku, 0, int64(sudoku.CountEmpty()), true) > 1
+	return count, limit > 0 && count >= limit
 }
 
-// internalCount will loop through all possible combinations and count the number of solutions it finds.
-// By supplying true to `breakIfMultiple` it will break if it finds more than one solutions.
-func (s *Sudoku) internalCount(sudoku *Sudoku, count, totalEmpty int64, breakIfMultiple bool) int64 {
-	// If we've filled all of the initially empty slots, we increment the counter.
-	if totalEmpty == 0 {
-		count++
+// CountSolutionsUpToContext is `CountSolutionsUpTo`, but it stops with an `AbortError` once the
+// context is done or its budget runs out (see `WithBudget`), along with the solutions it counted
+// so far.
+func (s *Sudoku) CountSolutionsUpToContext(ctx context.Context, limit int64) (int64, bool, error) {
+	l := newLimiter(ctx)
+	count, _ := s.findSolutions(limit, false, l)
+
+	if l.stopped() {
+		return count, false, l.err
 	}
 
-	if breakIfMultiple && count > 1 {
-		return count
+	return count, limit > 0 && count >= limit, nil
+}
+
+// SolutionsUpTo returns the solutions of the board as boards of their own, up to `limit` of
+// them, along with whether it stopped at the limit (see `CountSolutionsUpTo`). It's handy for
+// showing the other solutions of a puzzle that should only have one.
+func (s *Sudoku) SolutionsUpTo(limit int64) ([]*Sudoku, bool) {
+	count, solutions := s.findSolutions(limit, true, nil)
+
+	return solutions, limit > 0 && count >= limit
+}
+
+// SolutionsUpToContext is `SolutionsUpTo`, but it stops with an `AbortError` once the context is
+// done or its budget runs out (see `WithBudget`), along with the solutions it found so far.
+func (s *Sudoku) SolutionsUpToContext(ctx context.Context, limit int64) ([]*Sudoku, bool, error) {
+	l := newLimiter(ctx)
+	count, solutions := s.findSolutions(limit, true, l)
+
+	if l.stopped() {
+		return solutions, false, l.err
 	}
 
-	for i := 0; i < 9; i++ {
-		row := sudoku.GetRow(i)
+	return solutions, limit > 0 && count >= limit, nil
+}
 
-		for j, num := range row {
-			if num != 0 {
-				continue
-			}
+// countSolutions counts the solutions of the board, until the limiter stops it. It stops once
+// it has found `limit` solutions, unless `limit` is 0.
+func (s *Sudoku) countSolutions(limit int64, l *limiter) int64 {
+	count, _ := s.findSolutions(limit, false, l)
+
+	return count
+}
 
-			column := sudoku.GetCol(j)
-			box := sudoku.GetBoxFromRowCol(i, j)
-			possibilites := getVHPossibilities(row, column, box)
+// findSolutions counts the solutions of the board, until the limiter stops it, and returns them
+// as well if `keep` is set. It stops once it has found `limit` solutions, unless `limit` is 0.
+func (s *Sudoku) findSolutions(limit int64, keep bool, l *limiter) (int64, []*Sudoku) {
+	var count int64
+	var solutions []*Sudoku
 
-			// Recursively run through all of the possibilities and try to fill in all of
-			// the empty cells.
-			for _, possibility := range possibilites {
-				box.Insert(uint8(j%3), uint8(i%3), possibility)
-				count = s.internalCount(sudoku, count, totalEmpty-1, breakIfMultiple)
-				box.Insert(uint8(j%3), uint8(i%3), 0)
+	// solution adds a copy of the board to the solutions, for a solution to be written to.
+	solution := func() *Sudoku {
+		board := &Sudoku{}
+		board.Copy(s)
+		solutions = append(solutions, board)
 
-				if breakIfMultiple && count > 1 {
-					return count
-				}
+		return board
+	}
+
+	if s.usesDLX() {
+		x, candidates := sudokuCover(s)
+		x.limit = l
+		x.Search(func(rows []int) bool {
+			count++
+
+			if keep {
+				writeCover(solution(), rows, candidates)
 			}
 
-			return count
-		}
+			return limit > 0 && count >= limit
+		})
+
+		return count, solutions
 	}
 
-	return count
+	g := newGrid(s)
+	g.limit = l
+	defer g.release()
+	g.search(func() bool {
+		count++
+
+		if keep {
+			g.write(solution())
+		}
+
+		return limit > 0 && count >= limit
+	})
+
+	return count, solutions
+}
+
+// HasMultipleSolutions returns true if there are multiple solutions, or false if there
+// is only one.
+func (s *Sudoku) HasMultipleSolutions() bool {
+	_, multiple := s.CountSolutionsUpTo(2)
+
+	return multiple
 }
 
 // Copy copies a sudoku board into this instance.
 func (s *Sudoku) Copy(board *Sudoku) {
-	s.Init()
+	// A board of the same geometry keeps its boxes and its random numbers, so that copying a
+	// board over and over (e.g. to solve the same puzzle again) doesn't allocate.
+	reuse := s.rand != nil && len(s.Board) == int(board.N) && s.N == board.N &&
+		s.BoxWidth == board.BoxWidth && s.BoxHeight == board.BoxHeight
+
+	s.N = board.N
+	s.BoxWidth = board.BoxWidth
+	s.BoxHeight = board.BoxHeight
+	s.Symmetry = board.Symmetry
+	s.SymmetryMask = board.SymmetryMask
+	s.Solver = board.Solver
+	s.Constraints = board.Constraints
+
+	if reuse {
+		s.count = 0
+		s.stats = Stats{}
+		s.Eliminations = nil
+		s.Notes = nil
+		s.reseed(s.Seed)
+	} else {
+		s.Init()
+	}
 
 	for i, box := range board.Board {
-		s.Board[i].SetNumbers(box.GetNumbers())
+		s.Board[i].SetNumbers(box.numbers)
+	}
+
+	if board.Eliminations != nil {
+		s.Eliminations = append([]CandidateSet{}, board.Eliminations...)
+	}
+
+	if board.Notes != nil {
+		s.Notes = append([]CandidateSet{}, board.Notes...)
 	}
 
-	s.N = board.N
 	s.Seed = board.Seed
 }
 
@@ -563,64 +1042,172 @@ func (s *Sudoku) Save(fileName string) error {
 	return nil
 }
 
-// Print displays the board in stdout.
-func (s *Sudoku) Print(showRich bool) {
-	if showRich {
-		printLine(0)
+// Load reads a board from a JSON file, like the ones that `Save` creates. The file can also be
+// a `Document`, in which case its puzzle is returned.
+func Load(fileName string) (*Sudoku, error) {
+	sudokuJson, err := os.ReadFile(fileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Only documents have a version.
+	var header struct {
+		Version int `json:"version"`
 	}
 
-	for i := 0; i < 3; i++ {
-		box1 := s.Board[i*3]
-		box2 := s.Board[i*3+1]
-		box3 := s.Board[i*3+2]
+	if json.Unmarshal(sudokuJson, &header) == nil && header.Version != 0 {
+		doc, err := LoadDocument(fileName)
 
-		for j := 0; j < 3; j++ {
-			row := make([]uint8, 3)
-			copy(row, box1.GetRow(j))
+		if err != nil {
+			return nil, err
+		}
 
-			row = append(row, box2.GetRow(j)...)
-			row = append(row, box3.GetRow(j)...)
+		puzzle, _, err := doc.Boards()
 
-			if showRich {
-				fmt.Print("\xe2\x95\x91")
+		return puzzle, err
+	}
+
+	board := &Sudoku{}
+
+	if err = json.Unmarshal(sudokuJson, board); err != nil {
+		return nil, fmt.Errorf("unable to load %s: %w", fileName, err)
+	}
+
+	return board, nil
+}
+
+// UnmarshalJSON is used by the JSON module to read a board, as `Save` writes it. The size of
+// the board and of its boxes are validated, along with every number, so that the board is
+// ready to be used, as if `Init` was run and the numbers were inserted one by one.
+func (s *Sudoku) UnmarshalJSON(data []byte) error {
+	// The alias has the same fields, but not this method, so that it can be decoded as usual.
+	type sudokuJson Sudoku
+	var board sudokuJson
+
+	if err := json.Unmarshal(data, &board); err != nil {
+		return err
+	}
+
+	if board.N == 0 {
+		board.N = 9
+	}
+
+	n := int(board.N)
+
+	if err := ValidateSize(n); err != nil {
+		return err
+	}
+
+	if board.BoxWidth != 0 || board.BoxHeight != 0 {
+		if err := ValidateBoxSize(n, int(board.BoxWidth), int(board.BoxHeight)); err != nil {
+			return err
+		}
+	}
+
+	if len(board.Board) != n {
+		return fmt.Errorf("the board has %d boxes instead of %d", len(board.Board), n)
+	}
+
+	if board.Symmetry == CustomSymmetry {
+		if err := ValidateSymmetryMask(n, board.SymmetryMask); err != nil {
+			return err
+		}
+	}
+
+	for _, constraint := range board.Constraints {
+		for _, cell := range constraint {
+			if cell.Row < 0 || cell.Col < 0 || cell.Row >= n || cell.Col >= n {
+				return fmt.Errorf("the constraints have a cell outside of the board (%s)", cell)
 			}
+		}
+	}
 
-			for k, v := range row {
-				end := ""
+	if len(board.Eliminations) != 0 && len(board.Eliminations) != n*n {
+		return fmt.Errorf("the board has eliminations for %d cells instead of %d", len(board.Eliminations), n*n)
+	}
 
-				if showRich {
-					end = "\xe2\x94\x82"
+	if len(board.Notes) != 0 && len(board.Notes) != n*n {
+		return fmt.Errorf("the board has notes for %d cells instead of %d", len(board.Notes), n*n)
+	}
 
-					if (k-2)%3 == 0 {
-						end = "\xe2\x95\x91"
-					}
+	boxes := board.Board
+	eliminations, notes := board.Eliminations, board.Notes
+	*s = Sudoku(board)
+	s.Init()
 
-					fmt.Print(" ")
-				}
+	for i, box := range boxes {
+		if box == nil || int(box.N) != n {
+			return fmt.Errorf("box %d doesn't have %d numbers", i+1, n)
+		}
 
-				if v != 0 {
-					fmt.Print(v, " ", end)
-				} else {
-					fmt.Print("  ", end)
+		for pos, num := range box.numbers {
+			if !s.Board[i].InsertPos(pos, num) {
+				return fmt.Errorf("invalid number %d at position %d of box %d", num, pos+1, i+1)
+			}
+		}
+	}
+
+	s.Eliminations, s.Notes = eliminations, notes
+
+	return nil
+}
+
+// Print displays the board in stdout.
+func (s *Sudoku) Print(showRich bool) {
+	n := int(s.N)
+	w, h := s.BoxWidth, s.BoxHeight
+
+	if showRich {
+		printLine(n, int(w), lineTop)
+	}
+
+	for i := 0; i < n; i++ {
+		row := s.GetRow(i)
+
+		if showRich {
+			fmt.Print("\xe2\x95\x91")
+		}
+
+		for k, v := range row {
+			end := ""
+
+			if showRich {
+				end = "\xe2\x94\x82"
+
+				if (k+1)%int(w) == 0 {
+					end = "\xe2\x95\x91"
 				}
+
+				fmt.Print(" ")
 			}
 
-			fmt.Println()
+			if v != 0 {
+				fmt.Print(string(Symbol(v)), " ", end)
+			} else {
+				fmt.Print("  ", end)
+			}
+		}
+
+		fmt.Println()
 
-			if showRich && (i != 2 || (i == 2 && j < 2)) {
-				printLine(j + 3)
+		if showRich && i < n-1 {
+			if (i+1)%int(h) == 0 {
+				printLine(n, int(w), lineThick)
+			} else {
+				printLine(n, int(w), lineThin)
 			}
 		}
 	}
 
 	if showRich {
-		printLine(9)
+		printLine(n, int(w), lineBottom)
 	}
 }
 
 // GetBox returns a box in a specific position.
 func (s *Sudoku) GetBox(idx int) *Box {
-	if idx >= 9 {
+	if idx < 0 || idx >= int(s.N) {
 		return nil
 	}
 
@@ -629,151 +1216,194 @@ func (s *Sudoku) GetBox(idx int) *Box {
 
 // String returns a string representation of the sudoku board.
 func (s *Sudoku) String() string {
-	str := ""
+	var str strings.Builder
 
 	for _, b := range s.Board {
 		for _, j := range b.GetNumbers() {
-			char := "."
-
 			if j > 0 {
-				char = strconv.Itoa(int(j))
+				str.WriteByte(Symbol(j))
+			} else {
+				str.WriteByte('.')
 			}
-
-			str = fmt.Sprintf("%s%s", str, char)
 		}
 	}
 
-	return str
+	return str.String()
 }
 
-func printLine(i int) {
-	if i == 0 {
+const (
+	lineTop = iota
+	lineThin
+	lineThick
+	lineBottom
+)
+
+// printLine prints one of the horizontal borders of a board with `n` columns, which are grouped
+// in boxes of `boxWidth`.
+func printLine(n, boxWidth, kind int) {
+	switch kind {
+	case lineTop:
 		fmt.Print("\xe2\x95\x94")
-	} else if i == 9 {
+	case lineBottom:
 		fmt.Print("\xe2\x95\x9a")
-	} else {
-		if (i)%5 == 0 {
-			fmt.Print("\xe2\x95\xa0")
-		} else {
-			fmt.Print("\xe2\x95\x9f")
-		}
+	case lineThick:
+		fmt.Print("\xe2\x95\xa0")
+	default:
+		fmt.Print("\xe2\x95\x9f")
 	}
 
-	for l := 0; l < 35; l++ {
-		if l-11 >= 0 && (l-11)%12 == 0 {
-			if i == 0 {
+	for l := 0; l < n*4-1; l++ {
+		if (l-3)%4 == 0 && ((l+1)/4)%boxWidth == 0 {
+			switch kind {
+			case lineTop:
 				fmt.Print("\xe2\x95\xa6")
-			} else if i == 9 {
+			case lineBottom:
 				fmt.Print("\xe2\x95\xa9")
-			} else if (i-2)%3 == 0 {
+			case lineThick:
 				fmt.Print("\xe2\x95\xac")
-			} else {
+			default:
 				fmt.Print("\xe2\x95\xab")
 			}
 		} else if (l-3)%4 == 0 {
-			if i == 0 {
+			switch kind {
+			case lineTop:
 				fmt.Print("\xe2\x95\xa4")
-			} else if i == 9 {
+			case lineBottom:
 				fmt.Print("\xe2\x95\xa7")
-			} else if (i-2)%3 == 0 {
+			case lineThick:
 				fmt.Print("\xe2\x95\xaa")
-			} else {
+			default:
 				fmt.Print("\xe2\x94\xbc")
 			}
-		} else if i == 0 || i == 9 || (i-2)%3 == 0 {
+		} else if kind != lineThin {
 			fmt.Print("\xe2\x95\x90")
 		} else {
 			fmt.Print("\xe2\x94\x80")
 		}
 	}
 
-	if i == 0 {
+	switch kind {
+	case lineTop:
 		fmt.Print("\xe2\x95\x97")
-	} else if i == 9 {
+	case lineBottom:
 		fmt.Print("\xe2\x95\x9d")
-	} else {
-		if (i)%5 == 0 {
-			fmt.Print("\xe2\x95\xa3")
-		} else {
-			fmt.Print("\xe2\x95\xa2")
-		}
+	case lineThick:
+		fmt.Print("\xe2\x95\xa3")
+	default:
+		fmt.Print("\xe2\x95\xa2")
 	}
 
 	fmt.Println()
 }
 
-// ParseBoard parses a valid sudoku board. Empty slots are represented with a ".". Slots are
-// printed from the first 3x3 grid all the way to the last one.
+// ParseBoard parses a valid sudoku board. Empty slots are represented with a "." (or a "0").
+// Slots are printed from the first box all the way to the last one. For a 9x9 board these are
+// the following 3x3 boxes:
 // [1] [2] [3]
 // [4] [5] [6]
 // [7] [8] [9]
-// And the numbers within each 3x3 box are placed in the same order as the 3x3 boxes.
+// And the numbers within each box are placed in the same order as the boxes. The size of the
+// board is derived from the length of the string; boards bigger than 9x9 use letters after the
+// digits (e.g. "A" is 10).
 func ParseBoard(boardStr string) (*Sudoku, error) {
-	board := &Sudoku{}
+	return ParseBoardWithBoxSize(boardStr, 0, 0)
+}
+
+// ParseBoardWithBoxSize parses a board just like `ParseBoard`, but for boards whose boxes
+// are `boxWidth` columns wide and `boxHeight` rows tall (e.g. a 12x12 board with 3x4 boxes).
+// The default size of the boxes is used if both are 0.
+func ParseBoardWithBoxSize(boardStr string, boxWidth, boxHeight int) (*Sudoku, error) {
+	chars := []rune(boardStr)
+	n := int(math.Sqrt(float64(len(chars))))
+
+	if n*n != len(chars) || ValidateSize(n) != nil {
+		return nil, fmt.Errorf("invalid board length %d", len(chars))
+	}
+
+	if boxWidth != 0 || boxHeight != 0 {
+		if err := ValidateBoxSize(n, boxWidth, boxHeight); err != nil {
+			return nil, err
+		}
+	}
+
+	board := &Sudoku{N: uint8(n), BoxWidth: uint8(boxWidth), BoxHeight: uint8(boxHeight)}
 	board.Init()
 
-	for i, c := range boardStr {
-		boxIdx := (i / 9)
+	for i, c := range chars {
+		boxIdx := (i / n)
 		box := board.GetBox(boxIdx)
 
-		if c != '.' {
-			num, err := strconv.Atoi(string(c))
+		if c == '.' || c == '0' {
+			continue
+		}
 
-			if err != nil {
-				return nil, fmt.Errorf("invalid character \"%c\" at index %d", c, i)
-			}
+		num := ParseSymbol(c)
 
-			if !box.InsertPos(i-(boxIdx*9), uint8(num)) {
-				errStr := fmt.Sprintf("Unable to insert \"%c\" at index %d (box: %d, box position: )", c, i, boxIdx)
-				return nil, errors.New(errStr)
-			}
+		if num == 0 || num > board.N {
+			return nil, fmt.Errorf("invalid character \"%c\" at index %d", c, i)
+		}
+
+		if !box.InsertPos(i-(boxIdx*n), num) {
+			errStr := fmt.Sprintf("Unable to insert \"%c\" at index %d (box: %d, box position: %d)", c, i, boxIdx, i-(boxIdx*n))
+			return nil, errors.New(errStr)
 		}
 	}
 
 	return board, nil
 }
 
-// getVHPossibilities gets the vertical and horizontal possibilities.
-func getVHPossibilities(row, col []uint8, box *Box) []uint8 {
-	possibilites := make([]uint8, 0)
+// ValidateSize returns an error if a board can't have `n` rows and columns.
+func ValidateSize(n int) error {
+	if n < 4 || n > MaxN {
+		return fmt.Errorf("unsupported board size %d", n)
+	}
 
-	for i := 1; i <= 9; i++ {
-		if box.Has(uint8(i)) {
-			continue
-		}
+	if w, h := boxDimensions(uint8(n)); w == 0 || h == 0 {
+		return fmt.Errorf("unsupported board size %d", n)
+	}
 
-		found := false
+	return nil
+}
 
-		for _, num := range row {
-			if num == 0 {
-				continue
-			}
+// ValidateBoxSize returns an error if the boxes of a board with `n` rows and columns can't be
+// `width` columns wide and `height` rows tall.
+func ValidateBoxSize(n, width, height int) error {
+	if err := ValidateSize(n); err != nil {
+		return err
+	}
 
-			if num == uint8(i) {
-				found = true
-				break
-			}
-		}
+	if width < 2 || height < 2 || width*height != n {
+		return fmt.Errorf("unsupported box size %dx%d for a %dx%d board", width, height, n, n)
+	}
 
-		if found {
-			continue
-		}
+	return nil
+}
 
-		for _, num := range col {
-			if num == 0 {
-				continue
-			}
+// Symbol returns the character which represents the number `n`.
+func Symbol(n uint8) byte {
+	if n == 0 || int(n) > len(symbols) {
+		return '.'
+	}
 
-			if num == uint8(i) {
-				found = true
-				break
-			}
-		}
+	return symbols[n-1]
+}
+
+// ParseSymbol returns the number that a character represents, or 0 if it's not a valid one.
+func ParseSymbol(c rune) uint8 {
+	idx := strings.IndexRune(symbols, unicode.ToUpper(c))
+
+	return uint8(idx + 1)
+}
 
-		if !found {
-			possibilites = append(possibilites, uint8(i))
+// boxDimensions returns the default width and height of the boxes of a board with `n` rows
+// and columns. The boxes are as close to a square as possible, but never taller than they are
+// wide. Both are 0 if there's no such box with at least 2 rows (e.g. when `n` is prime).
+func boxDimensions(n uint8) (uint8, uint8) {
+	for h := uint8(math.Sqrt(float64(n))); h >= 2; h-- {
+		if n%h == 0 {
+			return n / h, h
 		}
 	}
 
-	return possibilites
+	return 0, 0
 }
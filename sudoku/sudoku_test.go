@@ -1,6 +1,9 @@
 package sudoku_test
 
 import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
@@ -45,6 +48,49 @@ func TestCountSolutions(t *testing.T) {
 	}
 }
 
+func TestCountSolutionsUpTo(t *testing.T) {
+	for _, solver := range []sudoku.Solver{sudoku.BacktrackingSolver, sudoku.DLXSolver} {
+		// This board has 72 solutions.
+		s, _ := sudoku.ParseRowMajor("1...............")
+		s.Solver = solver
+
+		limits := []struct {
+			limit  int64
+			count  int64
+			capped bool
+		}{{10, 10, true}, {72, 72, true}, {100, 72, false}, {0, 72, false}}
+
+		for _, l := range limits {
+			if count, capped := s.CountSolutionsUpTo(l.limit); count != l.count || capped != l.capped {
+				t.Errorf("Expected %d solutions up to %d with the %s solver, got %d", l.count, l.limit, solver, count)
+			}
+		}
+
+		solutions, capped := s.SolutionsUpTo(5)
+		seen := make(map[string]bool)
+
+		for _, solution := range solutions {
+			if solution.CountEmpty() != 0 || solution.GetCell(0, 0) != 1 || solution.CountSolutions() != 1 {
+				t.Errorf("Invalid solution %s", solution)
+			}
+
+			seen[solution.String()] = true
+		}
+
+		if len(seen) != 5 || !capped || s.CountEmpty() != 15 {
+			t.Errorf("Expected 5 distinct solutions with the %s solver, got %d", solver, len(seen))
+		}
+	}
+
+	s := initSudoku()
+	solutions, capped := s.SolutionsUpTo(10)
+	s.Solve()
+
+	if len(solutions) != 1 || capped || !solutions[0].IsEqual(s) {
+		t.Errorf("Expected the single solution of the board, got %d", len(solutions))
+	}
+}
+
 func TestIsEqual(t *testing.T) {
 	s1 := initSudoku()
 	s2 := initSudoku()
@@ -53,3 +99,222 @@ func TestIsEqual(t *testing.T) {
 		t.Error("The boards are equal but IsEqual said they're not")
 	}
 }
+
+func TestGenerateSizes(t *testing.T) {
+	for _, n := range []uint8{4, 16} {
+		s := &sudoku.Sudoku{N: n, Seed: 1}
+		s.Init()
+		s.Fill()
+
+		if s.CountEmpty() != 0 {
+			t.Errorf("The %dx%d board wasn't filled", n, n)
+		}
+
+		puzzle := s.GeneratePuzzle()
+
+		if puzzle.CountEmpty() == 0 || puzzle.CountSolutions() != 1 {
+			t.Errorf("The %dx%d puzzle is supposed to have only one solution", n, n)
+		}
+
+		if !puzzle.Solve() || !puzzle.IsEqual(s) {
+			t.Errorf("The %dx%d puzzle didn't solve to the original board", n, n)
+		}
+	}
+}
+
+func TestGenerateStats(t *testing.T) {
+	for _, n := range []uint8{9, 16} {
+		board := &sudoku.Sudoku{N: n, Seed: 42}
+		board.Init()
+		board.Fill()
+		puzzle := board.GeneratePuzzle()
+		stats := board.GetStats()
+
+		if stats.Fills < 1 || stats.Fills != stats.Restarts+1 {
+			t.Errorf("Expected a fill for each restart and one more, got %+v", stats)
+		}
+
+		// Every retry of the 9x9 board moves on to the next seed.
+		if stats.Puzzles != board.GetCounter()+1 || stats.Removals < int64(puzzle.CountEmpty()/8) {
+			t.Errorf("Unexpected stats %+v after %d retries", stats, board.GetCounter())
+		}
+
+		board.Init()
+
+		if board.GetStats() != (sudoku.Stats{}) || board.GetCounter() != 0 {
+			t.Errorf("Expected Init to reset the stats and the counter, got %+v and %d", board.GetStats(), board.GetCounter())
+		}
+	}
+}
+
+func TestGenerateRectangularBoxes(t *testing.T) {
+	for _, size := range [][3]uint8{{6, 3, 2}, {8, 4, 2}, {12, 3, 4}} {
+		s := &sudoku.Sudoku{N: size[0], BoxWidth: size[1], BoxHeight: size[2], Seed: 3}
+		s.Init()
+		s.Fill()
+
+		for i := 0; i < int(s.N); i++ {
+			if len(s.GetRow(i)) != int(s.N) || len(s.GetCol(i)) != int(s.N) {
+				t.Fatalf("Row or column %d of the %dx%d board has the wrong length", i, s.N, s.N)
+			}
+		}
+
+		puzzle := s.GeneratePuzzle()
+
+		if puzzle.BoxWidth != size[1] || puzzle.BoxHeight != size[2] {
+			t.Errorf("The puzzle lost its %dx%d boxes", size[1], size[2])
+		}
+
+		if !puzzle.Solve() || !puzzle.IsEqual(s) {
+			t.Errorf("The %dx%d puzzle didn't solve to the original board", s.N, s.N)
+		}
+	}
+}
+
+func TestParseBoard(t *testing.T) {
+	s := &sudoku.Sudoku{N: 16, Seed: 2}
+	s.Init()
+	s.Fill()
+
+	board, err := sudoku.ParseBoard(s.String())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if board.N != 16 || !board.IsEqual(s) {
+		t.Error("The parsed board is not the same as the original one")
+	}
+
+	if _, err := sudoku.ParseBoard("..2..2....4..1."); err == nil {
+		t.Error("A board with an invalid length was parsed")
+	}
+
+	if _, err := sudoku.ParseBoard("..5..2....4..1.."); err == nil {
+		t.Error("A 4x4 board with a 5 was parsed")
+	}
+
+	if _, err := sudoku.ParseBoardWithBoxSize(s.String(), 8, 3); err == nil {
+		t.Error("A 16x16 board with 8x3 boxes was parsed")
+	}
+}
+
+func TestMinimize(t *testing.T) {
+	board := &sudoku.Sudoku{Seed: 5, Symmetry: sudoku.NoSymmetry}
+	board.Init()
+	board.Fill()
+
+	// Every clue of a full board can be emptied on its own.
+	if minimal, redundant, err := board.IsMinimal(); err != nil || minimal || len(redundant) != 81 {
+		t.Errorf("Expected all the clues of a full board to be redundant, got %d", len(redundant))
+	}
+
+	puzzle := &sudoku.Sudoku{}
+	puzzle.Copy(board)
+
+	if err := puzzle.Minimize(); err != nil {
+		t.Fatal(err)
+	}
+
+	if minimal, redundant, err := puzzle.IsMinimal(); err != nil || !minimal {
+		t.Errorf("The puzzle isn't minimal, redundant clues: %v", redundant)
+	}
+
+	if puzzle.CountSolutions() != 1 {
+		t.Error("The minimal puzzle doesn't have a unique solution")
+	}
+
+	empty := &sudoku.Sudoku{}
+	empty.Init()
+
+	if err := empty.Minimize(); err != sudoku.ErrNotUnique {
+		t.Errorf("Expected an empty board not to be minimized, got %v", err)
+	}
+}
+
+func TestBox(t *testing.T) {
+	box := &sudoku.Box{N: 6}
+	box.Init()
+
+	if !box.Insert(2, 1, 4) || box.Insert(0, 0, 4) {
+		t.Fatal("Expected a number to be inserted only once")
+	}
+
+	if !box.Has(4) || !box.RowHas(1, 4) || box.RowHas(0, 4) || !box.ColHas(2, 4) || box.ColHas(1, 4) {
+		t.Error("The box doesn't report the position of 4 correctly")
+	}
+
+	box.InsertPos(5, 0)
+
+	if box.Has(4) || !box.InsertPos(0, 4) {
+		t.Error("Emptying a cell didn't remove its number")
+	}
+
+	box.SetNumbers([]uint8{1, 2})
+
+	if box.Has(4) || !box.Has(2) || box.CountEmpty() != 4 {
+		t.Errorf("Unexpected numbers after setting them: %v", box.GetNumbers())
+	}
+
+	data := "[1" + strings.Repeat(",0", 256) + "]"
+
+	if err := json.Unmarshal([]byte(data), box); err == nil || box.N != 6 {
+		t.Errorf("A box of 257 numbers was loaded as a box of %d", box.N)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	s := &sudoku.Sudoku{N: 6, Seed: 7}
+	s.Init()
+	s.Fill()
+
+	puzzle := s.GeneratePuzzle()
+	puzzle.TogglePencilMark(0, 0, 3)
+	puzzle.Rating, _ = puzzle.Grade()
+
+	fileName := filepath.Join(t.TempDir(), "puzzle.json")
+
+	if err := puzzle.Save(fileName); err != nil {
+		t.Fatal(err)
+	}
+
+	board, err := sudoku.Load(fileName)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if board.N != 6 || board.BoxWidth != 3 || board.BoxHeight != 2 || !board.IsEqual(puzzle) {
+		t.Error("The loaded board is not the same as the saved one")
+	}
+
+	if board.Seed != puzzle.Seed || board.Rating == nil || board.Rating.Difficulty != puzzle.Rating.Difficulty {
+		t.Error("The seed or the rating of the board weren't loaded")
+	}
+
+	if board.PencilMarks(0, 0) != puzzle.PencilMarks(0, 0) {
+		t.Error("The pencil marks of the board weren't loaded")
+	}
+
+	if !board.Solve() || board.CountEmpty() != 0 {
+		t.Error("Unable to solve the loaded board")
+	}
+
+	invalid := map[string]string{
+		"size":       `{"n":7,"board":[]}`,
+		"box size":   `{"n":6,"box_width":2,"box_height":2,"board":[[],[],[],[],[],[]]}`,
+		"boxes":      `{"n":4,"board":[[1,2,3,4]]}`,
+		"box length": `{"n":4,"board":[[1,2,3,4],[0,0,0,0],[0,0,0,0],[0,0,0]]}`,
+		"number":     `{"n":4,"board":[[1,2,3,5],[0,0,0,0],[0,0,0,0],[0,0,0,0]]}`,
+		"duplicate":  `{"n":4,"board":[[1,2,3,1],[0,0,0,0],[0,0,0,0],[0,0,0,0]]}`,
+		"notes":      `{"n":4,"board":[[0,0,0,0],[0,0,0,0],[0,0,0,0],[0,0,0,0]],"notes":[[1]]}`,
+		// 260 numbers would wrap around to a box of 4.
+		"long box": `{"n":4,"board":[[1,2,3,4` + strings.Repeat(",0", 256) + `],[0,0,0,0],[0,0,0,0],[0,0,0,0]]}`,
+	}
+
+	for name, data := range invalid {
+		if err := json.Unmarshal([]byte(data), &sudoku.Sudoku{}); err == nil {
+			t.Errorf("A board with an invalid %s was loaded", name)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package sudoku
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Symmetry is the pattern which the clues of a generated puzzle follow. Cells which are mapped
+// onto each other by the symmetry are always emptied together.
+type Symmetry int
+
+const (
+	RotationalSymmetry  Symmetry = iota // Rotating the board by 180° (the default).
+	NoSymmetry                          // Every cell is emptied on its own.
+	HorizontalSymmetry                  // Mirroring the board top to bottom.
+	VerticalSymmetry                    // Mirroring the board left to right.
+	DiagonalSymmetry                    // Mirroring the board along both of its diagonals.
+	QuarterTurnSymmetry                 // Rotating the board by 90°.
+	DihedralSymmetry                    // All of the above at once.
+	CustomSymmetry                      // Following the groups of `SymmetryMask`.
+)
+
+var symmetryNames = []string{
+	"rotational", "none", "horizontal", "vertical", "diagonal", "rotational-90", "dihedral", "custom",
+}
+
+// String returns the name of the symmetry.
+func (sym Symmetry) String() string {
+	if sym < RotationalSymmetry || sym > CustomSymmetry {
+		return fmt.Sprintf("Symmetry(%d)", int(sym))
+	}
+
+	return symmetryNames[sym]
+}
+
+// MarshalText is used by the JSON module to write the symmetry by its name.
+func (sym Symmetry) MarshalText() ([]byte, error) {
+	if sym < RotationalSymmetry || sym > CustomSymmetry {
+		return nil, fmt.Errorf("invalid symmetry %d", int(sym))
+	}
+
+	return []byte(sym.String()), nil
+}
+
+// UnmarshalText is used by the JSON module to read the symmetry from its name.
+func (sym *Symmetry) UnmarshalText(text []byte) error {
+	symmetry, err := ParseSymmetry(string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*sym = symmetry
+
+	return nil
+}
+
+// ParseSymmetry returns the symmetry with a specific name, regardless of its case.
+func ParseSymmetry(name string) (Symmetry, error) {
+	for i, symmetryName := range symmetryNames {
+		if strings.EqualFold(name, symmetryName) {
+			return Symmetry(i), nil
+		}
+	}
+
+	return RotationalSymmetry, fmt.Errorf("unknown symmetry \"%s\"", name)
+}
+
+// ValidateSymmetryMask checks whether a mask can be used as a custom symmetry for a board of
+// size `n`. The mask has a character for every cell, row by row, and the cells which share a
+// character are emptied together, while the ones marked with '.' are emptied on their own.
+// Whitespace is ignored, so the mask can be split into lines.
+func ValidateSymmetryMask(n int, mask string) error {
+	cells := 0
+
+	for _, c := range mask {
+		if !unicode.IsSpace(c) {
+			cells++
+		}
+	}
+
+	if cells != n*n {
+		return fmt.Errorf("the symmetry mask has %d cells instead of %d", cells, n*n)
+	}
+
+	return nil
+}
+
+// orbitCache caches the orbits of every board size and symmetry which has been used so far,
+// apart from the custom ones.
+var orbitCache sync.Map
+
+// orbits returns the groups of cells, by their index row by row, which are emptied together.
+// Each group starts with its lowest index and the groups are sorted by it. They're shared, so
+// they must not be changed.
+func (s *Sudoku) orbits() [][]int {
+	n := int(s.N)
+
+	if s.Symmetry == CustomSymmetry {
+		return maskOrbits(n, s.SymmetryMask)
+	}
+
+	key := [2]int{n, int(s.Symmetry)}
+
+	if orbits, ok := orbitCache.Load(key); ok {
+		return orbits.([][]int)
+	}
+
+	orbitOf := make([]int, n*n)
+	orbits := make([][]int, 0)
+
+	for idx := range orbitOf {
+		orbitOf[idx] = -1
+	}
+
+	transforms := symmetryTransforms(n, s.Symmetry)
+
+	for idx := range orbitOf {
+		if orbitOf[idx] >= 0 {
+			continue
+		}
+
+		orbit := []int{idx}
+		orbitOf[idx] = len(orbits)
+
+		// Keep applying the transforms to the cells of the orbit until no new cells come up.
+		for i := 0; i < len(orbit); i++ {
+			row, col := orbit[i]/n, orbit[i]%n
+
+			for _, transform := range transforms {
+				r, c := transform(row, col)
+
+				if other := r*n + c; orbitOf[other] < 0 {
+					orbitOf[other] = len(orbits)
+					orbit = append(orbit, other)
+				}
+			}
+		}
+
+		orbits = append(orbits, orbit)
+	}
+
+	actual, _ := orbitCache.LoadOrStore(key, orbits)
+
+	return actual.([][]int)
+}
+
+// symmetryTransforms returns the transforms which generate a symmetry.
+func symmetryTransforms(n int, sym Symmetry) []func(row, col int) (int, int) {
+	last := n - 1
+	rotate := func(row, col int) (int, int) { return last - row, last - col }
+	mirrorRows := func(row, col int) (int, int) { return last - row, col }
+	mirrorCols := func(row, col int) (int, int) { return row, last - col }
+	mainDiagonal := func(row, col int) (int, int) { return col, row }
+	antiDiagonal := func(row, col int) (int, int) { return last - col, last - row }
+	quarterTurn := func(row, col int) (int, int) { return col, last - row }
+
+	switch sym {
+	case NoSymmetry:
+		return nil
+	case HorizontalSymmetry:
+		return []func(row, col int) (int, int){mirrorRows}
+	case VerticalSymmetry:
+		return []func(row, col int) (int, int){mirrorCols}
+	case DiagonalSymmetry:
+		return []func(row, col int) (int, int){mainDiagonal, antiDiagonal}
+	case QuarterTurnSymmetry:
+		return []func(row, col int) (int, int){quarterTurn}
+	case DihedralSymmetry:
+		return []func(row, col int) (int, int){quarterTurn, mainDiagonal}
+	default:
+		return []func(row, col int) (int, int){rotate}
+	}
+}
+
+// maskOrbits returns the groups of cells of a custom symmetry mask. Cells which the mask
+// doesn't cover are emptied on their own.
+func maskOrbits(n int, mask string) [][]int {
+	orbits := make([][]int, 0)
+	orbitOf := make(map[rune]int)
+	idx := 0
+
+	for _, c := range mask {
+		if unicode.IsSpace(c) {
+			continue
+		}
+
+		if idx >= n*n {
+			break
+		}
+
+		if i, ok := orbitOf[c]; ok && c != '.' {
+			orbits[i] = append(orbits[i], idx)
+		} else {
+			orbitOf[c] = len(orbits)
+			orbits = append(orbits, []int{idx})
+		}
+
+		idx++
+	}
+
+	for ; idx < n*n; idx++ {
+		orbits = append(orbits, []int{idx})
+	}
+
+	return orbits
+}
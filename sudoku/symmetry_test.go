@@ -0,0 +1,114 @@
+package sudoku_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+// isSymmetric checks whether the empty cells of a board stay empty when the cells are moved
+// with a transform.
+func isSymmetric(s *sudoku.Sudoku, transform func(n, row, col int) (int, int)) bool {
+	n := int(s.N)
+
+	for row := 0; row < n; row++ {
+		for col := 0; col < n; col++ {
+			r, c := transform(n, row, col)
+
+			if (s.GetCell(row, col) == 0) != (s.GetCell(r, c) == 0) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func TestGenerateSymmetries(t *testing.T) {
+	rotate := func(n, row, col int) (int, int) { return n - 1 - row, n - 1 - col }
+	mirrorRows := func(n, row, col int) (int, int) { return n - 1 - row, col }
+	mirrorCols := func(n, row, col int) (int, int) { return row, n - 1 - col }
+	mainDiagonal := func(n, row, col int) (int, int) { return col, row }
+	antiDiagonal := func(n, row, col int) (int, int) { return n - 1 - col, n - 1 - row }
+	quarterTurn := func(n, row, col int) (int, int) { return col, n - 1 - row }
+
+	tests := []struct {
+		symmetry   sudoku.Symmetry
+		transforms []func(n, row, col int) (int, int)
+	}{
+		{sudoku.RotationalSymmetry, []func(n, row, col int) (int, int){rotate}},
+		{sudoku.NoSymmetry, nil},
+		{sudoku.HorizontalSymmetry, []func(n, row, col int) (int, int){mirrorRows}},
+		{sudoku.VerticalSymmetry, []func(n, row, col int) (int, int){mirrorCols}},
+		{sudoku.DiagonalSymmetry, []func(n, row, col int) (int, int){mainDiagonal, antiDiagonal}},
+		{sudoku.QuarterTurnSymmetry, []func(n, row, col int) (int, int){quarterTurn}},
+		{sudoku.DihedralSymmetry, []func(n, row, col int) (int, int){quarterTurn, mirrorRows, mainDiagonal}},
+	}
+
+	for _, n := range []uint8{9, 6} {
+		for _, test := range tests {
+			board := &sudoku.Sudoku{N: n, Seed: 7, Symmetry: test.symmetry}
+			board.Init()
+			board.Fill()
+			puzzle := board.GeneratePuzzle()
+
+			if puzzle.CountSolutions() != 1 {
+				t.Errorf("The %dx%d puzzle with %s symmetry doesn't have a unique solution", n, n, test.symmetry)
+			}
+
+			for _, transform := range test.transforms {
+				if !isSymmetric(puzzle, transform) {
+					t.Errorf("The %dx%d puzzle doesn't have %s symmetry", n, n, test.symmetry)
+				}
+			}
+
+			// Hardening the puzzle keeps the symmetry too.
+			puzzle.Harden()
+
+			for _, transform := range test.transforms {
+				if !isSymmetric(puzzle, transform) {
+					t.Errorf("The hardened %dx%d puzzle doesn't have %s symmetry", n, n, test.symmetry)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateCustomSymmetry(t *testing.T) {
+	// Each column is emptied together with the opposite one, while the middle one isn't grouped.
+	mask := strings.Repeat("abcd.dcba\n", 9)
+
+	if err := sudoku.ValidateSymmetryMask(9, mask); err != nil {
+		t.Fatal(err)
+	}
+
+	board := &sudoku.Sudoku{Seed: 3, Symmetry: sudoku.CustomSymmetry, SymmetryMask: mask}
+	board.Init()
+	board.Fill()
+	puzzle := board.GeneratePuzzle()
+
+	if puzzle.CountSolutions() != 1 {
+		t.Error("The puzzle doesn't have a unique solution")
+	}
+
+	if !isSymmetric(puzzle, func(n, row, col int) (int, int) { return row, n - 1 - col }) {
+		t.Error("The puzzle doesn't follow the mask")
+	}
+
+	if sudoku.ValidateSymmetryMask(9, "ab") == nil {
+		t.Error("A short mask was accepted")
+	}
+}
+
+func TestParseSymmetry(t *testing.T) {
+	symmetry, err := sudoku.ParseSymmetry("Rotational-90")
+
+	if err != nil || symmetry != sudoku.QuarterTurnSymmetry {
+		t.Errorf("Unable to parse the symmetry: %v", err)
+	}
+
+	if _, err := sudoku.ParseSymmetry("spiral"); err == nil {
+		t.Error("An unknown symmetry was parsed")
+	}
+}
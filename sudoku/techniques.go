@@ -0,0 +1,729 @@
+package sudoku
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// findNakedSingle looks for a cell which only has one candidate left.
+func (l *logicGrid) findNakedSingle() *Step {
+	for idx, candidates := range l.candidates {
+		if l.cells[idx] != 0 || bits.OnesCount32(candidates) != 1 {
+			continue
+		}
+
+		placement := l.candidate(idx, uint8(bits.TrailingZeros32(candidates)))
+
+		return &Step{
+			Technique:   NakedSingle,
+			Cells:       []Cell{placement.Cell},
+			Placements:  []Candidate{placement},
+			Description: fmt.Sprintf("%s can only be %c", placement.Cell, Symbol(placement.Value)),
+		}
+	}
+
+	return nil
+}
+
+// findHiddenSingle looks for a number which only fits in one cell of a unit. Boxes are checked
+// first, since that's where people usually spot these.
+func (l *logicGrid) findHiddenSingle() *Step {
+	for i := range l.units {
+		u := (i + l.n*2) % len(l.units)
+
+		for num := uint8(1); int(num) <= l.n; num++ {
+			places := l.places(u, num)
+
+			if len(places) != 1 {
+				continue
+			}
+
+			placement := l.candidate(places[0], num)
+
+			return &Step{
+				Technique:   HiddenSingle,
+				Cells:       []Cell{placement.Cell},
+				Placements:  []Candidate{placement},
+				Description: fmt.Sprintf("%c can only go in %s within %s", Symbol(num), placement.Cell, l.unitName(u)),
+				area:        l.unitName(u),
+			}
+		}
+	}
+
+	return nil
+}
+
+// findNakedSubset looks for `k` cells of a unit which only have `k` candidates between them.
+// Those candidates have to go in these cells, so they're removed from the rest of the unit.
+func (l *logicGrid) findNakedSubset(k int) *Step {
+	for u, unit := range l.units {
+		cells := make([]int, 0)
+
+		for _, idx := range unit {
+			if count := bits.OnesCount32(l.candidates[idx]); l.cells[idx] == 0 && count >= 2 && count <= k {
+				cells = append(cells, idx)
+			}
+		}
+
+		var step *Step
+
+		combinations(len(cells), k, func(combo []int) bool {
+			var union uint32
+			subset := make([]int, k)
+
+			for i, c := range combo {
+				subset[i] = cells[c]
+				union |= l.candidates[cells[c]]
+			}
+
+			if bits.OnesCount32(union) != k {
+				return false
+			}
+
+			eliminations := make([]Candidate, 0)
+
+			for _, idx := range unit {
+				if !contains(subset, idx) {
+					eliminations = append(eliminations, l.eliminations(idx, union)...)
+				}
+			}
+
+			if len(eliminations) == 0 {
+				return false
+			}
+
+			step = &Step{
+				Technique:    nakedSubsets[k],
+				Cells:        l.cellList(subset),
+				Eliminations: eliminations,
+				Description: fmt.Sprintf("%s can only be %s, so these are removed from the rest of %s",
+					joinCells(l.cellList(subset)), joinNumbers(union), l.unitName(u)),
+			}
+
+			return true
+		})
+
+		if step != nil {
+			return step
+		}
+	}
+
+	return nil
+}
+
+// findHiddenSubset looks for `k` numbers which only fit in the same `k` cells of a unit. These
+// cells have to hold those numbers, so all their other candidates are removed.
+func (l *logicGrid) findHiddenSubset(k int) *Step {
+	for u, unit := range l.units {
+		nums := make([]uint8, 0)
+		positions := make(map[uint8]uint32)
+
+		for num := uint8(1); int(num) <= l.n; num++ {
+			for i, idx := range unit {
+				if l.candidates[idx]&(1<<num) != 0 {
+					positions[num] |= 1 << i
+				}
+			}
+
+			if count := bits.OnesCount32(positions[num]); count >= 1 && count <= k {
+				nums = append(nums, num)
+			}
+		}
+
+		var step *Step
+
+		combinations(len(nums), k, func(combo []int) bool {
+			var union, set uint32
+
+			for _, c := range combo {
+				union |= positions[nums[c]]
+				set |= 1 << nums[c]
+			}
+
+			if bits.OnesCount32(union) != k {
+				return false
+			}
+
+			subset := make([]int, 0, k)
+			eliminations := make([]Candidate, 0)
+
+			for i, idx := range unit {
+				if union&(1<<i) != 0 {
+					subset = append(subset, idx)
+					eliminations = append(eliminations, l.eliminations(idx, l.candidates[idx]&^set)...)
+				}
+			}
+
+			if len(eliminations) == 0 {
+				return false
+			}
+
+			step = &Step{
+				Technique:    hiddenSubsets[k],
+				Cells:        l.cellList(subset),
+				Eliminations: eliminations,
+				Description: fmt.Sprintf("%s can only go in %s within %s, so the other candidates are removed from these cells",
+					joinNumbers(set), joinCells(l.cellList(subset)), l.unitName(u)),
+			}
+
+			return true
+		})
+
+		if step != nil {
+			return step
+		}
+	}
+
+	return nil
+}
+
+// findPointing looks for a number which, within a box, only fits in one row or column. The
+// number has to go in that box, so it's removed from the rest of the row or column.
+func (l *logicGrid) findPointing() *Step {
+	for u := l.n * 2; u < len(l.units); u++ {
+		if step := l.findIntersection(u, PointingPair); step != nil {
+			return step
+		}
+	}
+
+	return nil
+}
+
+// findBoxLineReduction looks for a number which, within a row or column, only fits in one box.
+// The number has to go in that row or column, so it's removed from the rest of the box.
+func (l *logicGrid) findBoxLineReduction() *Step {
+	for u := 0; u < l.n*2; u++ {
+		if step := l.findIntersection(u, BoxLineReduction); step != nil {
+			return step
+		}
+	}
+
+	return nil
+}
+
+// findIntersection looks for a number whose places in the unit `u` are all in another unit too,
+// and removes it from the rest of the other unit.
+func (l *logicGrid) findIntersection(u int, technique Technique) *Step {
+	for num := uint8(1); int(num) <= l.n; num++ {
+		places := l.places(u, num)
+
+		if len(places) < 2 {
+			continue
+		}
+
+		for i := 0; i < 3; i++ {
+			other := l.unitsOf[places[0]][i]
+
+			if other == u {
+				continue
+			}
+
+			shared := true
+
+			for _, idx := range places[1:] {
+				if l.unitsOf[idx][i] != other {
+					shared = false
+					break
+				}
+			}
+
+			if !shared {
+				continue
+			}
+
+			eliminations := make([]Candidate, 0)
+
+			for _, idx := range l.units[other] {
+				if !contains(places, idx) {
+					eliminations = append(eliminations, l.eliminations(idx, 1<<num)...)
+				}
+			}
+
+			if len(eliminations) == 0 {
+				continue
+			}
+
+			return &Step{
+				Technique:    technique,
+				Cells:        l.cellList(places),
+				Eliminations: eliminations,
+				Description: fmt.Sprintf("%c can only go in %s within %s, so it's removed from the rest of %s",
+					Symbol(num), joinCells(l.cellList(places)), l.unitName(u), l.unitName(other)),
+			}
+		}
+	}
+
+	return nil
+}
+
+// findFish looks for `k` rows (or columns) in which a number only fits in the same `k` columns
+// (or rows). The number has to go in these intersections, so it's removed from the rest of the
+// columns (or rows). These are the X-Wing, Swordfish and Jellyfish.
+func (l *logicGrid) findFish(k int) *Step {
+	for num := uint8(1); int(num) <= l.n; num++ {
+		// Go over the rows first and then over the columns.
+		for base := 0; base <= l.n; base += l.n {
+			cover := l.n - base
+			lines := make([]int, 0)
+			positions := make(map[int]uint32)
+
+			for u := base; u < base+l.n; u++ {
+				for i, idx := range l.units[u] {
+					if l.candidates[idx]&(1<<num) != 0 {
+						positions[u] |= 1 << i
+					}
+				}
+
+				if count := bits.OnesCount32(positions[u]); count >= 2 && count <= k {
+					lines = append(lines, u)
+				}
+			}
+
+			var step *Step
+
+			combinations(len(lines), k, func(combo []int) bool {
+				var union uint32
+				baseLines := make([]int, k)
+
+				for i, c := range combo {
+					baseLines[i] = lines[c]
+					union |= positions[lines[c]]
+				}
+
+				if bits.OnesCount32(union) != k {
+					return false
+				}
+
+				cells := make([]int, 0)
+				eliminations := make([]Candidate, 0)
+
+				for i := 0; i < l.n; i++ {
+					if union&(1<<i) == 0 {
+						continue
+					}
+
+					for _, idx := range l.units[cover+i] {
+						if contains(baseLines, l.unitsOf[idx][base/l.n]) {
+							if l.candidates[idx]&(1<<num) != 0 {
+								cells = append(cells, idx)
+							}
+						} else {
+							eliminations = append(eliminations, l.eliminations(idx, 1<<num)...)
+						}
+					}
+				}
+
+				if len(eliminations) == 0 {
+					return false
+				}
+
+				names := make([]string, k)
+
+				for i, u := range baseLines {
+					names[i] = l.unitName(u)
+				}
+
+				step = &Step{
+					Technique:    fishes[k],
+					Cells:        l.cellList(cells),
+					Eliminations: eliminations,
+					Description: fmt.Sprintf("%c can only go in %s within %s, so it's removed from the rest of the lines crossing them",
+						Symbol(num), joinCells(l.cellList(cells)), joinNames(names)),
+				}
+
+				return true
+			})
+
+			if step != nil {
+				return step
+			}
+		}
+	}
+
+	return nil
+}
+
+// findXYWing looks for a cell with two candidates, XY, which sees a cell with XZ and another
+// one with YZ. Either of the last two has to be Z, so Z is removed from the cells that see both.
+func (l *logicGrid) findXYWing() *Step {
+	for pivot, candidates := range l.candidates {
+		if l.cells[pivot] != 0 || bits.OnesCount32(candidates) != 2 {
+			continue
+		}
+
+		wings := l.bivaluePeers(pivot)
+
+		for i, a := range wings {
+			for _, b := range wings[i+1:] {
+				shared := l.candidates[a] & l.candidates[b]
+				z := shared &^ candidates
+
+				// The wings need to share Z and split X and Y between them.
+				if bits.OnesCount32(shared) != 1 || z == 0 || (l.candidates[a]|l.candidates[b])&^z != candidates {
+					continue
+				}
+
+				if step := l.wingStep(XYWing, []int{pivot, a, b}, z); step != nil {
+					return step
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// findXYZWing looks for a cell with three candidates, XYZ, which sees a cell with XZ and another
+// one with YZ. One of the three has to be Z, so Z is removed from the cells that see all three.
+func (l *logicGrid) findXYZWing() *Step {
+	for pivot, candidates := range l.candidates {
+		if l.cells[pivot] != 0 || bits.OnesCount32(candidates) != 3 {
+			continue
+		}
+
+		wings := l.bivaluePeers(pivot)
+
+		for i, a := range wings {
+			for _, b := range wings[i+1:] {
+				shared := l.candidates[a] & l.candidates[b]
+
+				if bits.OnesCount32(shared) != 1 || l.candidates[a]|l.candidates[b] != candidates {
+					continue
+				}
+
+				if step := l.wingStep(XYZWing, []int{pivot, a, b}, shared); step != nil {
+					return step
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// wingStep removes the number `z` from the cells which see all the cells of a wing that aren't
+// its pivot (and the pivot itself, if it has `z` as a candidate).
+func (l *logicGrid) wingStep(technique Technique, wing []int, z uint32) *Step {
+	eliminations := make([]Candidate, 0)
+	pattern := wing[1:]
+
+	if l.candidates[wing[0]]&z != 0 {
+		pattern = wing
+	}
+
+	for idx := range l.cells {
+		if contains(wing, idx) {
+			continue
+		}
+
+		seesAll := true
+
+		for _, w := range pattern {
+			if !l.sees(idx, w) {
+				seesAll = false
+				break
+			}
+		}
+
+		if seesAll {
+			eliminations = append(eliminations, l.eliminations(idx, z)...)
+		}
+	}
+
+	if len(eliminations) == 0 {
+		return nil
+	}
+
+	cells := l.cellList(wing)
+
+	return &Step{
+		Technique:    technique,
+		Cells:        cells,
+		Eliminations: eliminations,
+		Description: fmt.Sprintf("%s (%s) with %s (%s) and %s (%s) means one of them is %s, so it's removed from the cells that see them",
+			cells[0], joinNumbers(l.candidates[wing[0]]), cells[1], joinNumbers(l.candidates[wing[1]]),
+			cells[2], joinNumbers(l.candidates[wing[2]]), joinNumbers(z)),
+	}
+}
+
+// findSimpleColoring follows the cells of a number which are linked in pairs (where the number
+// only fits in two cells of a unit), giving the cells of each pair opposite colors. One of the
+// two colors is the true one, so if two cells of the same color see each other, that color is
+// false, and any other cell that sees both colors can't hold the number.
+func (l *logicGrid) findSimpleColoring() *Step {
+	for num := uint8(1); int(num) <= l.n; num++ {
+		links := l.strongLinks(num)
+		colors := make(map[int]int)
+
+		for start := range l.cells {
+			if _, ok := colors[start]; ok || len(links[start]) == 0 {
+				continue
+			}
+
+			// Color the chain that starts from this cell, alternating between 0 and 1.
+			colors[start] = 0
+			chain := []int{start}
+
+			for i := 0; i < len(chain); i++ {
+				for _, next := range links[chain[i]] {
+					if _, ok := colors[next]; !ok {
+						colors[next] = 1 - colors[chain[i]]
+						chain = append(chain, next)
+					}
+				}
+			}
+
+			if step := l.coloringStep(num, chain, colors); step != nil {
+				return step
+			}
+		}
+	}
+
+	return nil
+}
+
+// coloringStep looks for eliminations in a colored chain of a number.
+func (l *logicGrid) coloringStep(num uint8, chain []int, colors map[int]int) *Step {
+	// If two cells of the same color see each other, the whole color is false.
+	for i, a := range chain {
+		for _, b := range chain[i+1:] {
+			if colors[a] != colors[b] || !l.sees(a, b) {
+				continue
+			}
+
+			eliminations := make([]Candidate, 0)
+
+			for _, idx := range chain {
+				if colors[idx] == colors[a] {
+					eliminations = append(eliminations, l.candidate(idx, num))
+				}
+			}
+
+			return &Step{
+				Technique:    SimpleColoring,
+				Cells:        l.cellList(chain),
+				Eliminations: eliminations,
+				Description: fmt.Sprintf("Coloring the chain of %c puts %s and %s in the same color while they see each other, so %c is removed from all the cells of that color",
+					Symbol(num), l.cell(a), l.cell(b), Symbol(num)),
+			}
+		}
+	}
+
+	// Any other cell that sees both colors can't hold the number.
+	eliminations := make([]Candidate, 0)
+
+	for idx, candidates := range l.candidates {
+		if candidates&(1<<num) == 0 || contains(chain, idx) {
+			continue
+		}
+
+		seen := [2]bool{}
+
+		for _, c := range chain {
+			if l.sees(idx, c) {
+				seen[colors[c]] = true
+			}
+		}
+
+		if seen[0] && seen[1] {
+			eliminations = append(eliminations, l.candidate(idx, num))
+		}
+	}
+
+	if len(eliminations) == 0 {
+		return nil
+	}
+
+	return &Step{
+		Technique:    SimpleColoring,
+		Cells:        l.cellList(chain),
+		Eliminations: eliminations,
+		Description: fmt.Sprintf("Coloring the chain of %c starting at %s means one of the two colors holds it, so it's removed from the cells that see both",
+			Symbol(num), l.cell(chain[0])),
+	}
+}
+
+// findXChain looks for a chain of cells for a single number, which alternates between strong
+// links (the number only fits in these two cells of a unit) and weak ones (the two cells see
+// each other), and which starts and ends with a strong link. One of the two ends has to hold
+// the number, so it's removed from the cells that see both.
+func (l *logicGrid) findXChain() *Step {
+	for num := uint8(1); int(num) <= l.n; num++ {
+		links := l.strongLinks(num)
+
+		for start := range l.cells {
+			if len(links[start]) == 0 {
+				continue
+			}
+
+			if step := l.xChainFrom(num, start, links); step != nil {
+				return step
+			}
+		}
+	}
+
+	return nil
+}
+
+// xChainFrom searches for the shortest useful X-Chain that starts from a cell.
+func (l *logicGrid) xChainFrom(num uint8, start int, links map[int][]int) *Step {
+	type node struct {
+		idx    int
+		strong bool // Whether the node was reached with a strong link.
+	}
+
+	parents := map[node]node{{start, false}: {-1, false}}
+	queue := []node{{start, false}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		next := make([]int, 0)
+
+		if current.strong {
+			for _, idx := range l.peers[current.idx] {
+				if l.candidates[idx]&(1<<num) != 0 {
+					next = append(next, idx)
+				}
+			}
+		} else {
+			next = links[current.idx]
+		}
+
+		for _, idx := range next {
+			n := node{idx, !current.strong}
+
+			if _, ok := parents[n]; ok || idx == start {
+				continue
+			}
+
+			parents[n] = current
+			queue = append(queue, n)
+
+			if !n.strong {
+				continue
+			}
+
+			chain := []int{}
+
+			for p := n; p.idx >= 0; p = parents[p] {
+				chain = append([]int{p.idx}, chain...)
+			}
+
+			// A single strong link is already covered by the simpler techniques.
+			if len(chain) < 4 {
+				continue
+			}
+
+			eliminations := make([]Candidate, 0)
+
+			for other, candidates := range l.candidates {
+				if candidates&(1<<num) != 0 && other != idx && l.sees(other, start) && l.sees(other, idx) {
+					eliminations = append(eliminations, l.candidate(other, num))
+				}
+			}
+
+			if len(eliminations) == 0 {
+				continue
+			}
+
+			return &Step{
+				Technique:    XChain,
+				Cells:        l.cellList(chain),
+				Eliminations: eliminations,
+				Description: fmt.Sprintf("The chain of %c through %s means either %s or %s holds it, so it's removed from the cells that see both",
+					Symbol(num), joinCells(l.cellList(chain)), l.cell(start), l.cell(idx)),
+			}
+		}
+	}
+
+	return nil
+}
+
+// strongLinks returns, for every cell, the cells which form a strong link with it for a number.
+func (l *logicGrid) strongLinks(num uint8) map[int][]int {
+	links := make(map[int][]int)
+
+	for u := range l.units {
+		places := l.places(u, num)
+
+		if len(places) != 2 {
+			continue
+		}
+
+		a, b := places[0], places[1]
+
+		if !contains(links[a], b) {
+			links[a] = append(links[a], b)
+			links[b] = append(links[b], a)
+		}
+	}
+
+	return links
+}
+
+// places returns the cells of a unit where a number is a candidate.
+func (l *logicGrid) places(u int, num uint8) []int {
+	places := make([]int, 0)
+
+	for _, idx := range l.units[u] {
+		if l.candidates[idx]&(1<<num) != 0 {
+			places = append(places, idx)
+		}
+	}
+
+	return places
+}
+
+// bivaluePeers returns the peers of a cell which have exactly two candidates.
+func (l *logicGrid) bivaluePeers(idx int) []int {
+	peers := make([]int, 0)
+
+	for _, peer := range l.peers[idx] {
+		if bits.OnesCount32(l.candidates[peer]) == 2 {
+			peers = append(peers, peer)
+		}
+	}
+
+	return peers
+}
+
+// eliminations returns the candidates out of a set of numbers that a cell has.
+func (l *logicGrid) eliminations(idx int, set uint32) []Candidate {
+	eliminations := make([]Candidate, 0)
+	set &= l.candidates[idx]
+
+	for set != 0 {
+		num := bits.TrailingZeros32(set)
+		set &^= 1 << num
+		eliminations = append(eliminations, l.candidate(idx, uint8(num)))
+	}
+
+	return eliminations
+}
+
+var nakedSubsets = map[int]Technique{2: NakedPair, 3: NakedTriple, 4: NakedQuad}
+var hiddenSubsets = map[int]Technique{2: HiddenPair, 3: HiddenTriple, 4: HiddenQuad}
+var fishes = map[int]Technique{2: XWing, 3: Swordfish, 4: Jellyfish}
+
+// contains returns whether a list of indices holds a specific one.
+func contains(list []int, idx int) bool {
+	for _, i := range list {
+		if i == idx {
+			return true
+		}
+	}
+
+	return false
+}
+
+// joinNames returns a readable list of names, like "row 1 and row 5".
+func joinNames(names []string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
@@ -0,0 +1,102 @@
+package sudoku
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Trace is the full path of the logical solver through a puzzle, which can be saved as JSON or
+// printed as text (see `String`).
+type Trace struct {
+	Puzzle   string      `json:"puzzle"`
+	Steps    []TraceStep `json:"steps"`
+	Solved   bool        `json:"solved"`
+	Solution string      `json:"solution"`
+}
+
+// TraceStep is a step of the logical solver along with how it changed the candidates.
+type TraceStep struct {
+	Step
+	Changes []CandidateChange `json:"changes"`
+}
+
+// CandidateChange is how the candidates of a cell changed with a step. A cell which got a number
+// is left without candidates.
+type CandidateChange struct {
+	Cell
+	Before CandidateSet `json:"before"`
+	After  CandidateSet `json:"after"`
+	Placed uint8        `json:"placed,omitempty"`
+}
+
+// Trace solves a copy of the puzzle logically, like `SolveLogically` does, and records every
+// step along with the candidates of the cells it changed, from before and after it.
+func (s *Sudoku) Trace(allowGuessing bool) *Trace {
+	puzzle := &Sudoku{}
+	puzzle.Copy(s)
+
+	trace := &Trace{
+		Puzzle: s.String(),
+		Steps:  make([]TraceStep, 0),
+	}
+
+	_, trace.Solved = puzzle.solveLogically(allowGuessing, func(l *logicGrid, step *Step, before []uint32) {
+		traceStep := TraceStep{Step: *step, Changes: make([]CandidateChange, 0)}
+		placed := make(map[int]uint8)
+
+		for _, p := range step.Placements {
+			placed[l.index(p.Cell)] = p.Value
+		}
+
+		for idx, candidates := range l.candidates {
+			if candidates == before[idx] {
+				continue
+			}
+
+			traceStep.Changes = append(traceStep.Changes, CandidateChange{
+				Cell:   l.cell(idx),
+				Before: CandidateSet(before[idx]),
+				After:  CandidateSet(candidates),
+				Placed: placed[idx],
+			})
+		}
+
+		trace.Steps = append(trace.Steps, traceStep)
+	}, nil)
+
+	trace.Solution = puzzle.String()
+
+	return trace
+}
+
+// String returns the trace as text, with a numbered line for every step, followed by the
+// candidates of the cells it changed.
+func (t *Trace) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Puzzle: %s\n", t.Puzzle)
+
+	for i, step := range t.Steps {
+		fmt.Fprintf(&b, "%d. %s: %s\n", i+1, step.Technique, step.Description)
+
+		for _, change := range step.Changes {
+			after := change.After.String()
+
+			if change.Placed != 0 {
+				after = fmt.Sprintf("%c (placed)", Symbol(change.Placed))
+			} else if after == "" {
+				after = "-"
+			}
+
+			fmt.Fprintf(&b, "   %s: %s -> %s\n", change.Cell, change.Before, after)
+		}
+	}
+
+	if t.Solved {
+		fmt.Fprintf(&b, "Solved: %s\n", t.Solution)
+	} else {
+		fmt.Fprintf(&b, "Stuck at: %s\n", t.Solution)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,67 @@
+package sudoku_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/wisepythagoras/go-sudoku-gen/sudoku"
+)
+
+func TestTrace(t *testing.T) {
+	s := initSudoku()
+	trace := s.Trace(false)
+	steps, _ := initSudoku().SolveLogically(false)
+
+	if !trace.Solved || len(trace.Steps) != len(steps) {
+		t.Fatalf("Expected %d steps, got %d", len(steps), len(trace.Steps))
+	}
+
+	if s.CountEmpty() == 0 || trace.Puzzle != s.String() {
+		t.Error("The trace changed the puzzle")
+	}
+
+	solution := initSudoku()
+	solution.Solve()
+
+	if trace.Solution != solution.String() {
+		t.Error("The trace doesn't end with the solution")
+	}
+
+	for i, step := range trace.Steps {
+		if step.Technique != steps[i].Technique || len(step.Changes) == 0 {
+			t.Fatalf("Step %d doesn't match the solver: %+v", i+1, step)
+		}
+
+		for _, change := range step.Changes {
+			// The candidates only ever go away.
+			if change.After&^change.Before != 0 || change.After == change.Before {
+				t.Errorf("Unexpected change in step %d: %+v", i+1, change)
+			}
+
+			if change.Placed != 0 && (change.After != 0 || !change.Before.Has(change.Placed)) {
+				t.Errorf("Unexpected placement in step %d: %+v", i+1, change)
+			}
+		}
+	}
+
+	text := trace.String()
+
+	if !strings.HasPrefix(text, "Puzzle: "+trace.Puzzle) || !strings.Contains(text, "1. "+string(steps[0].Technique)) ||
+		!strings.Contains(text, "Solved: "+trace.Solution) {
+		t.Errorf("Unexpected text trace:\n%s", text)
+	}
+
+	data, err := json.Marshal(trace)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded sudoku.Trace
+
+	if err := json.Unmarshal(data, &decoded); err != nil || len(decoded.Steps) != len(trace.Steps) ||
+		decoded.Steps[0].Changes[0] != trace.Steps[0].Changes[0] {
+		t.Errorf("The JSON trace doesn't match the original one: %v", err)
+	}
+}